@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/dashboard"
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/term"
+)
+
+// startDashboard shows the jobs of the plan in a full-screen dashboard if --tui is set, stop restores the terminal and prints the final status of the jobs
+func startDashboard(ctx context.Context, input *Input, plan *model.Plan) (context.Context, func()) {
+	if !input.tui {
+		return ctx, func() {}
+	}
+	switch {
+	case !term.IsTerminal(int(os.Stdout.Fd())) || !term.IsTerminal(int(os.Stdin.Fd())):
+		log.Warn("Not showing the dashboard, --tui requires an interactive terminal")
+		return ctx, func() {}
+	case input.breakOnFailure || len(input.breakBefore) > 0 || len(input.protectedEnvironments) > 0:
+		log.Warn("Not showing the dashboard, breakpoints and protected environments prompt on the terminal")
+		return ctx, func() {}
+	}
+
+	d := dashboard.New(plan)
+	// Ctrl-C does not raise SIGINT while the terminal is in raw mode, so the dashboard interrupts the run itself:
+	// the first Ctrl-C cancels the jobs gracefully and the second one stops the run right away
+	ctx, cancel := context.WithCancel(ctx)
+	interrupt := func() {
+		if !common.Interrupt(ctx) {
+			cancel()
+		}
+	}
+	stop, err := d.Start(os.Stdout, os.Stdin, interrupt)
+	if err != nil {
+		log.Warnf("Not showing the dashboard: %v", err)
+		return ctx, cancel
+	}
+	output := log.StandardLogger().Out
+	log.SetOutput(d.GlobalLogWriter())
+
+	return runner.WithJobLoggerFactory(ctx, d), func() {
+		stop()
+		cancel()
+		log.SetOutput(output)
+		d.WriteSummary(os.Stdout)
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/runner"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/term"
+)
+
+const (
+	debugShell    = "Open a shell"
+	debugContinue = "Continue"
+	debugRetry    = "Retry the step"
+	debugAbort    = "Abort the job"
+)
+
+// newStepDebugger asks what to do when a job pauses at a breakpoint, nil if no breakpoints are set
+func newStepDebugger(breakOnFailure bool, breakBefore []string) runner.StepDebugger {
+	if !breakOnFailure && len(breakBefore) == 0 {
+		return nil
+	}
+	// parallel jobs must not prompt at the same time
+	var mu sync.Mutex
+	return func(ctx context.Context, breakpoint runner.Breakpoint, shell common.Executor) (runner.DebugAction, error) {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			log.Warnf("Ignoring the breakpoint at '%s' of job '%s', breakpoints require an interactive terminal", breakpoint.Step, breakpoint.Job)
+			return runner.DebugContinue, nil
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		stepName, _, _ := strings.Cut(breakpoint.StepName, "\n")
+		message := fmt.Sprintf("Job '%s' paused before step '%s' (%s)", breakpoint.Job, stepName, breakpoint.Step)
+		options := []string{debugContinue, debugAbort}
+		if breakpoint.Err != nil {
+			message = fmt.Sprintf("Step '%s' (%s) of job '%s' failed: %v", stepName, breakpoint.Step, breakpoint.Job, breakpoint.Err)
+			options = []string{debugRetry, debugContinue, debugAbort}
+		}
+		if shell != nil {
+			options = append([]string{debugShell}, options...)
+		}
+
+		for {
+			answer := ""
+			if err := survey.AskOne(&survey.Select{Message: message, Options: options}, &answer); err != nil {
+				return runner.DebugAbort, err
+			}
+			switch answer {
+			case debugShell:
+				fmt.Println("Exit the shell to return to the breakpoint")
+				if err := shell(ctx); err != nil {
+					log.Errorf("Unable to open a shell: %v", err)
+				}
+			case debugRetry:
+				return runner.DebugRetry, nil
+			case debugAbort:
+				return runner.DebugAbort, nil
+			default:
+				return runner.DebugContinue, nil
+			}
+		}
+	}
+}
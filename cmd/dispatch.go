@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/nektos/act/pkg/model"
+	"golang.org/x/term"
+)
+
+// promptDispatchInputs asks for the workflow_dispatch inputs of the workflows of the plan which were not provided,
+// the environment inputs are chosen among the given environments
+func promptDispatchInputs(plan *model.Plan, inputs map[string]string, environments []string) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil
+	}
+
+	seen := map[*model.Workflow]bool{}
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			if seen[run.Workflow] {
+				continue
+			}
+			seen[run.Workflow] = true
+			config := run.Workflow.WorkflowDispatchConfig()
+			if config == nil {
+				continue
+			}
+
+			names := make([]string, 0, len(config.Inputs))
+			for name := range config.Inputs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if _, ok := inputs[name]; ok {
+					continue
+				}
+				value, err := promptDispatchInput(name, config.Inputs[name], environments)
+				if err != nil {
+					return err
+				}
+				inputs[name] = value
+			}
+		}
+	}
+	return nil
+}
+
+func promptDispatchInput(name string, input model.WorkflowDispatchInput, environments []string) (string, error) {
+	message := name
+	if input.Description != "" {
+		message = fmt.Sprintf("%s (%s)", name, input.Description)
+	}
+
+	switch input.Type {
+	case "boolean":
+		answer := false
+		err := survey.AskOne(&survey.Confirm{Message: message, Default: input.Default == "true"}, &answer)
+		return strconv.FormatBool(answer), err
+	case "choice":
+		answer := ""
+		prompt := &survey.Select{Message: message, Options: input.Options}
+		if input.Default != "" {
+			prompt.Default = input.Default
+		}
+		err := survey.AskOne(prompt, &answer)
+		return answer, err
+	case "environment":
+		if len(environments) > 0 {
+			answer := ""
+			prompt := &survey.Select{Message: message, Options: environments}
+			if slices.Contains(environments, input.Default) {
+				prompt.Default = input.Default
+			}
+			err := survey.AskOne(prompt, &answer)
+			return answer, err
+		}
+	}
+
+	answer := ""
+	validators := []survey.AskOpt{survey.WithValidator(func(ans interface{}) error {
+		_, err := input.Coerce(fmt.Sprint(ans), environments)
+		return err
+	})}
+	if input.Required {
+		validators = append(validators, survey.WithValidator(survey.Required))
+	}
+	err := survey.AskOne(&survey.Input{Message: message, Default: input.Default}, &answer, validators...)
+	return answer, err
+}
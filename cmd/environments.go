@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/term"
+)
+
+// plannedEnvironments returns the names of the deployment environments of the jobs of the plans, dynamic is set if
+// some environments are only known once their jobs run, e.g. an environment chosen by an input or a reusable workflow
+func plannedEnvironments(plans ...*model.Plan) (names []string, dynamic bool) {
+	for _, plan := range plans {
+		if plan == nil {
+			continue
+		}
+		for _, stage := range plan.Stages {
+			for _, run := range stage.Runs {
+				job := run.Job()
+				if job == nil {
+					continue
+				}
+				if job.Uses != "" {
+					dynamic = true
+					continue
+				}
+				environment := job.DeploymentEnvironment()
+				switch {
+				case environment == nil || environment.Name == "":
+				case strings.Contains(environment.Name, "${{"):
+					dynamic = true
+				case !slices.ContainsFunc(names, func(name string) bool { return strings.EqualFold(name, environment.Name) }):
+					names = append(names, environment.Name)
+				}
+			}
+		}
+	}
+	return names, dynamic
+}
+
+// readEnvironmentEnvs reads the per-environment variants of a secret or var file, e.g. .secrets.production for .secrets
+// or .secrets.production.yml for .secrets.yml, keyed by the name of the environment.
+// Only the variants of the given environments are read, all the variants are read if dynamic is set
+// and the variants which can't be parsed are then skipped.
+func readEnvironmentEnvs(path string, environments []string, dynamic bool, caseInsensitive bool) (map[string]map[string]string, error) {
+	if len(environments) == 0 && !dynamic {
+		return nil, nil
+	}
+	ext := filepath.Ext(path)
+	if ext != ".yml" && ext != ".yaml" {
+		ext = ""
+	}
+	prefix := strings.TrimSuffix(path, ext) + "."
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]map[string]string{}
+	for _, match := range matches {
+		name := strings.TrimSuffix(strings.TrimPrefix(match, prefix), ext)
+		if name == "" || name == "yml" || name == "yaml" || strings.Contains(name, ".") {
+			continue
+		}
+		// the names of environments are case insensitive
+		planned := slices.ContainsFunc(environments, func(environment string) bool { return strings.EqualFold(environment, name) })
+		if !planned && !dynamic {
+			continue
+		}
+		log.Debugf("Loading the environment '%s' from %s", name, match)
+		envs, err := readEnvFile(match, caseInsensitive)
+		if err != nil {
+			if !planned {
+				log.Warnf("Skipping the environment '%s': %v", name, err)
+				continue
+			}
+			return nil, fmt.Errorf("unable to load the environment '%s': %w", name, err)
+		}
+		values[name] = envs
+	}
+	return values, nil
+}
+
+// loadEnvironments reads the secrets and vars of the deployment environments used by the jobs of the plans
+func loadEnvironments(input *Input, plans ...*model.Plan) (secrets map[string]map[string]string, vars map[string]map[string]string, err error) {
+	names, dynamic := plannedEnvironments(plans...)
+	if secrets, err = readEnvironmentEnvs(input.Secretfile(), names, dynamic, true); err != nil {
+		return nil, nil, err
+	}
+	if vars, err = readEnvironmentEnvs(input.Varfile(), names, dynamic, false); err != nil {
+		return nil, nil, err
+	}
+	return secrets, vars, nil
+}
+
+// newEnvironmentApprover asks for an interactive approval before a job deploys to one of the protected environments
+func newEnvironmentApprover(protected []string) runner.EnvironmentApprover {
+	if len(protected) == 0 {
+		return nil
+	}
+	// parallel jobs must not prompt at the same time
+	var mu sync.Mutex
+	return func(_ context.Context, job string, environment string) (bool, error) {
+		if !slices.ContainsFunc(protected, func(p string) bool { return strings.EqualFold(p, environment) }) {
+			return true, nil
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return false, fmt.Errorf("the environment '%s' is protected and requires an interactive terminal for the approval", environment)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		approved := false
+		err := survey.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("Approve the deployment of '%s' to the environment '%s'?", job, environment),
+		}, &approved)
+		return approved, err
+	}
+}
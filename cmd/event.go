@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/nektos/act/pkg/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func newEventCommand(ctx context.Context, input *Input) *cobra.Command {
+	eventCmd := &cobra.Command{
+		Use:   "event",
+		Short: "Work with event payloads",
+		Args:  cobra.NoArgs,
+	}
+
+	var output string
+	generateCmd := &cobra.Command{
+		Use:       "generate <event>",
+		Short:     "Generate the payload of an event from the local git repository",
+		Long:      "Generate the webhook payload of an event from the checked out branch or tag of the local git repository, e.g. a pull request of the current branch against the default branch. The payload can be passed to act with --eventpath.",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: model.GeneratedEvents,
+		RunE: func(_ *cobra.Command, args []string) error {
+			event, err := model.GenerateEventPayload(ctx, args[0], input.eventPayloadOptions(parseEnvs(input.inputs)))
+			if err != nil {
+				return err
+			}
+			content, err := json.MarshalIndent(event, "", "  ")
+			if err != nil {
+				return err
+			}
+			content = append(content, '\n')
+			if output == "" {
+				_, err = os.Stdout.Write(content)
+				return err
+			}
+			return os.WriteFile(output, content, 0o644)
+		},
+		// flags of the .actrc are meant for the run command
+		FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	}
+	generateCmd.Flags().StringVarP(&output, "output", "o", "", "file to write the payload to instead of stdout")
+	generateCmd.Flags().StringArrayVar(&input.inputs, "input", []string{}, "input of a workflow_dispatch event (e.g. --input myinput=foo)")
+	generateCmd.Flags().StringVar(&input.defaultBranch, "defaultbranch", "", "the name of the main branch, read from the git remote if empty")
+	generateCmd.Flags().StringVar(&input.remoteName, "remote-name", "origin", "git remote name that will be used to retrieve url of git repo")
+
+	eventCmd.AddCommand(generateCmd)
+	return eventCmd
+}
+
+func (i *Input) eventPayloadOptions(inputs map[string]string) model.EventPayloadOptions {
+	return model.EventPayloadOptions{
+		RepoPath:       i.Workdir(),
+		GithubInstance: i.githubInstance,
+		RemoteName:     i.remoteName,
+		DefaultBranch:  i.defaultBranch,
+		Actor:          i.actor,
+		Inputs:         inputs,
+	}
+}
+
+// readEventPayload reads the payload of the event file, or generates it from the local repository with --generate-event
+func readEventPayload(ctx context.Context, input *Input, eventName string) map[string]interface{} {
+	event := map[string]interface{}{}
+	if eventPath := input.EventPath(); eventPath != "" {
+		content, err := os.ReadFile(eventPath)
+		if err == nil {
+			err = json.Unmarshal(content, &event)
+		}
+		if err != nil {
+			log.Warnf("Unable to read event payload '%s' to evaluate workflow filters: %v", eventPath, err)
+		}
+	} else if input.generateEvent {
+		generated, err := model.GenerateEventPayload(ctx, eventName, input.eventPayloadOptions(nil))
+		if err != nil {
+			log.Warnf("Unable to generate the payload of the %s event: %v", eventName, err)
+		} else {
+			event = generated
+		}
+	}
+	return event
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nektos/act/pkg/artifacts"
+	"github.com/nektos/act/pkg/runner"
+	log "github.com/sirupsen/logrus"
+)
+
+// streamEvents writes the events of the run to the target of --event-stream, close closes the target once the run completed
+func streamEvents(ctx context.Context, input *Input, config *runner.Config) (context.Context, func(), error) {
+	if input.eventStream == "" {
+		return ctx, func() {}, nil
+	}
+	w, err := openEventStream(input.eventStream)
+	if err != nil {
+		return ctx, nil, fmt.Errorf("unable to open the event stream '%s': %w", input.eventStream, err)
+	}
+	config.Events = newEventWriter(w)
+	ctx = artifacts.WithUploadHandler(ctx, func(runID string, name string, size int64) {
+		config.Events(runner.Event{
+			Type:     runner.EventArtifactUploaded,
+			Time:     time.Now(),
+			Artifact: &runner.Artifact{Name: name, Size: size, RunID: runID},
+		})
+	})
+	return ctx, func() { _ = w.Close() }, nil
+}
+
+// openEventStream opens a file, fd:<n> for a file descriptor inherited from the parent process or unix:<path> for a unix socket
+func openEventStream(target string) (io.WriteCloser, error) {
+	if fd, ok := strings.CutPrefix(target, "fd:"); ok {
+		n, err := strconv.Atoi(fd)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid file descriptor '%s'", fd)
+		}
+		return os.NewFile(uintptr(n), target), nil
+	}
+	if path, ok := strings.CutPrefix(target, "unix:"); ok {
+		return net.Dial("unix", path)
+	}
+	return os.Create(target)
+}
+
+// newEventWriter writes the events as NDJSON, one JSON object per line
+func newEventWriter(w io.Writer) runner.EventHandler {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+	failed := false
+	return func(event runner.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failed {
+			return
+		}
+		if err := encoder.Encode(event); err != nil {
+			// e.g. the reader of the socket went away, the run goes on without the events
+			failed = true
+			log.Warnf("Stopped writing the event stream: %v", err)
+		}
+	}
+}
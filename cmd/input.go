@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"path/filepath"
+	"time"
 
+	"github.com/nektos/act/pkg/runhistory"
+	"github.com/nektos/act/pkg/workflowtest"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -13,6 +16,25 @@ type Input struct {
 	workflowsPath                      string
 	autodetectEvent                    bool
 	eventPath                          string
+	generateEvent                      bool
+	chainWorkflowRuns                  bool
+	breakOnFailure                     bool
+	breakBefore                        []string
+	fromStep                           string
+	toStep                             string
+	onlySteps                          []string
+	sarifFile                          string
+	checksFile                         string
+	reportJUnit                        string
+	reportMarkdown                     string
+	reportHTML                         string
+	tui                                bool
+	collapseGroups                     bool
+	eventStream                        string
+	logDir                             string
+	cancelTimeout                      time.Duration
+	stepOverrides                      string
+	scheduleAt                         time.Time // simulated time of the schedule event, set by the schedule command
 	reuseContainers                    bool
 	bindWorkdir                        bool
 	secrets                            []string
@@ -29,6 +51,7 @@ type Input struct {
 	secretfile                         string
 	varfile                            string
 	insecureSecrets                    bool
+	protectedEnvironments              []string
 	defaultBranch                      string
 	privileged                         bool
 	usernsMode                         string
@@ -67,6 +90,11 @@ type Input struct {
 	validate                           bool
 	strict                             bool
 	concurrentJobs                     int
+	historyPath                        string
+	noHistory                          bool
+	rerun                              *runhistory.Run // run of the history to run again, set by the rerun command
+	rerunFailed                        bool
+	testRun                            *workflowtest.Run // run of a test case, set by the test command
 }
 
 func (i *Input) resolve(path string) string {
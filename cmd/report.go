@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nektos/act/pkg/report"
+	"github.com/nektos/act/pkg/runner"
+	"golang.org/x/term"
+)
+
+// collectReports collects the records of the jobs for the reports written once the run completed, nil in dry run mode
+func collectReports(input *Input, config *runner.Config) *report.Collector {
+	if input.dryrun {
+		return nil
+	}
+	collector := &report.Collector{}
+	recordJob := config.RecordJob
+	config.RecordJob = func(record runner.JobRecord) {
+		if recordJob != nil {
+			recordJob(record)
+		}
+		collector.RecordJob(record)
+	}
+	return collector
+}
+
+// writeReports prints the annotations and the summaries of the steps, unless the logs are JSON, and writes the report
+// files of the run
+func writeReports(input *Input, collector *report.Collector) error {
+	if collector == nil {
+		return nil
+	}
+	records := collector.Records()
+	workflowsDir, err := filepath.Rel(input.Workdir(), input.WorkflowsPath())
+	if err != nil {
+		workflowsDir = input.WorkflowsPath()
+	}
+	annotations := report.Annotations(records, filepath.ToSlash(workflowsDir))
+	// the output of --json is a stream of JSON log entries, the text of the terminal reports would break it
+	if !input.jsonLogger {
+		if len(annotations) > 0 {
+			fmt.Println()
+			report.WriteAnnotationSummary(os.Stdout, annotations)
+		}
+		if report.HasSummaries(records) {
+			fmt.Println()
+			report.RenderSummaries(os.Stdout, records, term.IsTerminal(int(os.Stdout.Fd())))
+		}
+	}
+
+	if input.sarifFile != "" {
+		if err := writeReportFile(input.sarifFile, func(w io.Writer) error { return report.WriteSARIF(w, annotations) }); err != nil {
+			return err
+		}
+	}
+	if input.checksFile != "" {
+		if err := writeReportFile(input.checksFile, func(w io.Writer) error { return report.WriteChecks(w, annotations) }); err != nil {
+			return err
+		}
+	}
+	if input.reportJUnit != "" {
+		if err := writeReportFile(input.reportJUnit, func(w io.Writer) error { return report.WriteJUnit(w, records) }); err != nil {
+			return err
+		}
+	}
+	if input.reportMarkdown != "" {
+		if err := writeReportFile(input.reportMarkdown, func(w io.Writer) error { report.WriteMarkdown(w, records); return nil }); err != nil {
+			return err
+		}
+	}
+	if input.reportHTML != "" {
+		if err := writeReportFile(input.reportHTML, func(w io.Writer) error { return report.WriteHTML(w, records) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeReportFile(name string, write func(w io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write the report '%s': %w", name, err)
+	}
+	return f.Close()
+}
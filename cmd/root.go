@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"runtime"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/adrg/xdg"
@@ -31,6 +33,7 @@ import (
 	"github.com/nektos/act/pkg/container"
 	"github.com/nektos/act/pkg/gh"
 	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runhistory"
 	"github.com/nektos/act/pkg/runner"
 )
 
@@ -77,6 +80,7 @@ func createRootCommand(ctx context.Context, input *Input, version string) *cobra
 	rootCmd.Flags().StringVar(&input.remoteName, "remote-name", "origin", "git remote name that will be used to retrieve url of git repo")
 	rootCmd.Flags().StringArrayVarP(&input.secrets, "secret", "s", []string{}, "secret to make available to actions with optional value (e.g. -s mysecret=foo or -s mysecret)")
 	rootCmd.Flags().StringArrayVar(&input.vars, "var", []string{}, "variable to make available to actions with optional value (e.g. --var myvar=foo or --var myvar)")
+	rootCmd.Flags().StringArrayVar(&input.protectedEnvironments, "protected-environment", []string{}, "deployment environment that requires an interactive approval before a job runs in it (e.g. --protected-environment production)")
 	rootCmd.Flags().StringArrayVarP(&input.envs, "env", "", []string{}, "env to make available to actions with optional value (e.g. --env myenv=foo or --env myenv)")
 	rootCmd.Flags().StringArrayVarP(&input.inputs, "input", "", []string{}, "action input to make available to actions (e.g. --input myinput=foo)")
 	rootCmd.Flags().StringArrayVarP(&input.platforms, "platform", "P", []string{}, "custom image to use per platform (e.g. -P ubuntu-18.04=nektos/act-environments-ubuntu:18.04)")
@@ -86,7 +90,25 @@ func createRootCommand(ctx context.Context, input *Input, version string) *cobra
 	rootCmd.Flags().BoolVarP(&input.forceRebuild, "rebuild", "", true, "rebuild local action docker image(s) even if already present")
 	rootCmd.Flags().BoolVarP(&input.autodetectEvent, "detect-event", "", false, "Use first event type from workflow as event that triggered the workflow")
 	rootCmd.Flags().StringVarP(&input.eventPath, "eventpath", "e", "", "path to event JSON file")
+	rootCmd.Flags().BoolVar(&input.generateEvent, "generate-event", false, "generate the event payload from the local git repository if no event JSON file is given")
 	rootCmd.Flags().StringVar(&input.defaultBranch, "defaultbranch", "", "the name of the main branch")
+	rootCmd.Flags().BoolVar(&input.chainWorkflowRuns, "chain-workflow-runs", false, "after the workflows completed, run the workflows triggered by their workflow_run events")
+	rootCmd.Flags().BoolVar(&input.breakOnFailure, "break-on-failure", false, "pause a job when a step failed to open a shell in the environment of the step, retry the step or abort the job")
+	rootCmd.Flags().StringArrayVar(&input.breakBefore, "break-before", []string{}, "pause a job before a step to open a shell in the environment of the step (e.g. --break-before build.test for the step with the id test of the job build)")
+	rootCmd.Flags().StringVar(&input.sarifFile, "sarif-file", "", "write the errors, warnings and notices reported by the steps to a SARIF file")
+	rootCmd.Flags().StringVar(&input.checksFile, "checks-file", "", "write the errors, warnings and notices reported by the steps to a JSON file, in the format of the output of a check run of the GitHub Checks API")
+	rootCmd.Flags().StringVar(&input.reportJUnit, "report-junit", "", "write a JUnit XML report with a testsuite per job and a testcase per step to a file")
+	rootCmd.Flags().StringVar(&input.reportMarkdown, "report-markdown", "", "write a Markdown report with the results and durations of the jobs and steps and the summaries of the steps to a file")
+	rootCmd.Flags().StringVar(&input.reportHTML, "report-html", "", "write the report of --report-markdown as a self-contained HTML page to a file")
+	rootCmd.Flags().BoolVar(&input.collapseGroups, "collapse-groups", false, "replace the output of a ::group:: of a step by a single line once it ended, unless an error was logged in it, the output is written out if the step fails")
+	rootCmd.Flags().StringVar(&input.eventStream, "event-stream", "", "write the events of the run (plan, start and end of jobs and steps, outputs, annotations, artifacts, result) as NDJSON to a file, to fd:<n> or to unix:<socket path>")
+	rootCmd.Flags().DurationVar(&input.cancelTimeout, "cancel-timeout", 5*time.Minute, "time the steps using always() or cancelled() and the post steps get to run after the run was cancelled with Ctrl-C, before it is stopped right away as on a second Ctrl-C. The processes of steps in containers are signalled by the ACT_EXEC_ID variable of their environment, which needs sh, tr, grep and /proc in the image")
+	rootCmd.Flags().StringVar(&input.stepOverrides, "step-overrides", "", "YAML file with overrides which skip steps, replace them with a shell script or another action, or set their outputs and outcome, matched by the uses or the id of the steps")
+	rootCmd.Flags().StringVar(&input.logDir, "log-dir", "", "write the log of every job and of every step to a directory, laid out like the log archive of a workflow run of GitHub, with timestamps, masked secrets and without colors, the logs of previous runs of the workflows are replaced")
+	rootCmd.Flags().BoolVar(&input.tui, "tui", false, "show the stages, jobs and matrices with their status, elapsed time and current step in a full-screen terminal dashboard, select a job to follow its log")
+	rootCmd.Flags().StringVar(&input.fromStep, "from-step", "", "skip the steps before this step, by step id or index in the form [<job>.]<step> (with --reuse the job container of a previous run is kept, but its state is not restored from a snapshot)")
+	rootCmd.Flags().StringVar(&input.toStep, "to-step", "", "skip the steps after this step, by step id or index in the form [<job>.]<step>")
+	rootCmd.Flags().StringArrayVar(&input.onlySteps, "only-step", []string{}, "run only this step, by step id or index in the form [<job>.]<step>, skipped steps are reported as skipped in the steps context (can't be combined with --from-step or --to-step)")
 	rootCmd.Flags().BoolVar(&input.privileged, "privileged", false, "use privileged mode")
 	rootCmd.Flags().StringVar(&input.usernsMode, "userns", "", "user namespace to use")
 	rootCmd.Flags().StringVar(&input.containerRuntime, "container-runtime", "auto", "container runtime to use: auto, docker, podman")
@@ -132,6 +154,13 @@ func createRootCommand(ctx context.Context, input *Input, version string) *cobra
 	rootCmd.PersistentFlags().StringArrayVarP(&input.localRepository, "local-repository", "", []string{}, "Replaces the specified repository and ref with a local folder (e.g. https://github.com/test/test@v0=/home/act/test or test/test@v0=/home/act/test, the latter matches any hosts or protocols)")
 	rootCmd.PersistentFlags().BoolVar(&input.listOptions, "list-options", false, "Print a json structure of compatible options")
 	rootCmd.PersistentFlags().IntVar(&input.concurrentJobs, "concurrent-jobs", 0, "Maximum number of concurrent jobs to run. Default is the number of CPUs available.")
+	rootCmd.PersistentFlags().StringVar(&input.historyPath, "history-path", filepath.Join(CacheHomeDir, "acthistory"), "Defines the path where the history of the runs is stored.")
+	rootCmd.PersistentFlags().BoolVar(&input.noHistory, "no-history", false, "Do not record the run in the history")
+	rootCmd.AddCommand(newEventCommand(ctx, input))
+	rootCmd.AddCommand(newScheduleCommand(ctx, input, rootCmd))
+	rootCmd.AddCommand(newRunsCommand(input))
+	rootCmd.AddCommand(newRerunCommand(ctx, input, rootCmd))
+	rootCmd.AddCommand(newTestCommand(ctx, input, rootCmd))
 	rootCmd.SetArgs(args())
 	return rootCmd
 }
@@ -350,19 +379,11 @@ func readEnvs(path string, envs map[string]string) bool {
 
 func readEnvsEx(path string, envs map[string]string, caseInsensitive bool) bool {
 	if _, err := os.Stat(path); err == nil {
-		var env map[string]string
-		if ext := filepath.Ext(path); ext == ".yml" || ext == ".yaml" {
-			env, err = readYamlFile(path)
-		} else {
-			env, err = godotenv.Read(path)
-		}
+		env, err := readEnvFile(path, caseInsensitive)
 		if err != nil {
 			log.Fatalf("Error loading from %s: %v", path, err)
 		}
 		for k, v := range env {
-			if caseInsensitive {
-				k = strings.ToUpper(k)
-			}
 			if _, ok := envs[k]; !ok {
 				envs[k] = v
 			}
@@ -372,6 +393,28 @@ func readEnvsEx(path string, envs map[string]string, caseInsensitive bool) bool
 	return false
 }
 
+// readEnvFile reads a dotenv or yaml file, the keys are upper cased if caseInsensitive is set
+func readEnvFile(path string, caseInsensitive bool) (map[string]string, error) {
+	var env map[string]string
+	var err error
+	if ext := filepath.Ext(path); ext == ".yml" || ext == ".yaml" {
+		env, err = readYamlFile(path)
+	} else {
+		env, err = godotenv.Read(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !caseInsensitive {
+		return env, nil
+	}
+	upper := make(map[string]string, len(env))
+	for k, v := range env {
+		upper[strings.ToUpper(k)] = v
+	}
+	return upper, nil
+}
+
 func parseMatrix(matrix []string) map[string]map[string]bool {
 	// each matrix entry should be of the form - string:string
 	r := regexp.MustCompile(":")
@@ -505,13 +548,31 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 			filterEventName = events[0]
 		}
 
+		// the payload of an event is read from the event file or generated from the local repository
+		payloads := map[string]map[string]interface{}{}
+		eventPayload := func(eventName string) map[string]interface{} {
+			if _, ok := payloads[eventName]; !ok {
+				payloads[eventName] = readEventPayload(ctx, input, eventName)
+			}
+			return payloads[eventName]
+		}
+
+		// the trigger of an event is derived from the event payload and the local repository
+		triggers := map[string]*model.EventTrigger{}
+		eventTrigger := func(eventName string) *model.EventTrigger {
+			if _, ok := triggers[eventName]; !ok {
+				triggers[eventName] = newEventTrigger(ctx, input, eventName, eventPayload(eventName))
+			}
+			return triggers[eventName]
+		}
+
 		var plannerErr error
 		if jobID != "" {
 			log.Debugf("Preparing plan with a job: %s", jobID)
 			filterPlan, plannerErr = planner.PlanJob(jobID)
 		} else if filterEventName != "" {
 			log.Debugf("Preparing plan for a event: %s", filterEventName)
-			filterPlan, plannerErr = planner.PlanEvent(filterEventName)
+			filterPlan, plannerErr = planner.PlanTrigger(eventTrigger(filterEventName))
 		} else {
 			log.Debugf("Preparing plan with all jobs")
 			filterPlan, plannerErr = planner.PlanAll()
@@ -559,12 +620,21 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 		}
 
 		// build the plan for this run
-		if jobID != "" {
+		if input.rerun != nil {
+			log.Debugf("Planning the jobs of run %d", input.rerun.ID)
+			plan, plannerErr = planner.PlanWorkflowJobs(input.rerun.PlannedJobs())
+		} else if jobID != "" {
 			log.Debugf("Planning job: %s", jobID)
 			plan, plannerErr = planner.PlanJob(jobID)
+		} else if eventName == "schedule" && !input.scheduleAt.IsZero() {
+			log.Debugf("Planning scheduled workflows at: %s", input.scheduleAt)
+			plan, plannerErr = planner.PlanSchedule(input.scheduleAt)
+			if plan != nil && len(plan.Stages) == 0 {
+				return fmt.Errorf("no scheduled workflow fires at %s", input.scheduleAt.UTC().Format(time.RFC3339))
+			}
 		} else {
 			log.Debugf("Planning jobs for event: %s", eventName)
-			plan, plannerErr = planner.PlanEvent(eventName)
+			plan, plannerErr = planner.PlanTrigger(eventTrigger(eventName))
 		}
 		if plan != nil {
 			if len(plan.Stages) == 0 {
@@ -575,6 +645,19 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 			return plannerErr
 		}
 
+		// only the environments used by the planned jobs are loaded
+		environmentSecrets, environmentVars, err := loadEnvironments(input, plan)
+		if err != nil {
+			return err
+		}
+
+		// inputs of an event file take precedence, otherwise ask for the missing inputs of manually dispatched workflows
+		if eventName == "workflow_dispatch" && input.EventPath() == "" {
+			if err := promptDispatchInputs(plan, inputs, runner.EnvironmentNames(environmentSecrets, environmentVars)); err != nil {
+				return err
+			}
+		}
+
 		// check to see if the main branch was defined
 		defaultbranch, err := cmd.Flags().GetString("defaultbranch")
 		if err != nil {
@@ -613,6 +696,13 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 			log.Warnf(deprecationWarning, "container-cap-drop", fmt.Sprintf("--cap-drop=%s", input.containerCapDrop))
 		}
 
+		var stepOverrides []*runner.StepOverride
+		if input.stepOverrides != "" {
+			if stepOverrides, err = runner.ReadStepOverrides(input.stepOverrides); err != nil {
+				return err
+			}
+		}
+
 		// run the plan
 		config := &runner.Config{
 			Actor:                              input.actor,
@@ -632,6 +722,9 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 			Env:                                envs,
 			Secrets:                            secrets,
 			Vars:                               vars,
+			EnvironmentSecrets:                 environmentSecrets,
+			EnvironmentVars:                    environmentVars,
+			ApproveEnvironment:                 newEnvironmentApprover(input.protectedEnvironments),
 			Inputs:                             inputs,
 			Token:                              secrets["GITHUB_TOKEN"],
 			InsecureSecrets:                    input.insecureSecrets,
@@ -658,8 +751,49 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 			Matrix:                             matrixes,
 			ContainerNetworkMode:               docker_container.NetworkMode(input.networkName),
 			ConcurrentJobs:                     input.concurrentJobs,
+			ScheduleTime:                       input.scheduleAt,
+			BreakOnFailure:                     input.breakOnFailure,
+			BreakBefore:                        input.breakBefore,
+			Debugger:                           newStepDebugger(input.breakOnFailure, input.breakBefore),
+			FromStep:                           input.fromStep,
+			ToStep:                             input.toStep,
+			OnlySteps:                          input.onlySteps,
+			CollapseGroups:                     input.collapseGroups,
+			LogDir:                             input.logDir,
+			StepOverrides:                      stepOverrides,
+		}
+
+		if input.generateEvent && input.EventPath() == "" {
+			event := eventPayload(eventName)
+			if eventName == "workflow_dispatch" {
+				event["inputs"] = inputs
+			}
+			if len(event) > 0 {
+				eventJSON, err := json.Marshal(event)
+				if err != nil {
+					return err
+				}
+				config.EventJSON = string(eventJSON)
+			}
 		}
 		
+		if input.rerun != nil {
+			config.EventJSON = input.rerun.EventJSON
+			if input.rerunFailed {
+				config.ReusedJobs = input.rerun.ReusedJobs()
+			}
+		}
+		recorder := recordRun(input, runhistory.NewRun(plan, config, input.WorkflowsPath()), config)
+		collector := collectReports(input, config)
+		ctx, closeEvents, err := streamEvents(ctx, input, config)
+		if err != nil {
+			return err
+		}
+		defer closeEvents()
+		if input.testRun != nil {
+			ctx = input.testRun.Configure(ctx, config)
+		}
+
 		// Container runtime already configured early in process
 		if input.useNewActionCache || len(input.localRepository) > 0 {
 			if input.actionOfflineMode {
@@ -705,6 +839,8 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 		}
 
 		ctx = common.WithDryrun(ctx, input.dryrun)
+		ctx, stopCancelTimeout := common.WithCancelTimeout(ctx, input.cancelTimeout)
+		defer stopCancelTimeout()
 		if watch, err := cmd.Flags().GetBool("watch"); err != nil {
 			return err
 		} else if watch {
@@ -715,12 +851,24 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 			return plannerErr
 		}
 
-		executor := r.NewPlanExecutor(plan).Finally(func(_ context.Context) error {
+		ctx, stopDashboard := startDashboard(ctx, input, plan)
+		executor := r.NewPlanExecutor(plan)
+		if input.chainWorkflowRuns {
+			executor = chainWorkflowRuns(input, *config, plan, eventPayload(eventName), executor)
+		}
+		executor = executor.Finally(func(_ context.Context) error {
 			cancel()
 			_ = cacheHandler.Close()
 			return nil
 		})
 		err = executor(ctx)
+		stopDashboard()
+		if recorder != nil {
+			saveRun(input, recorder.Finish(err))
+		}
+		if reportErr := writeReports(input, collector); reportErr != nil {
+			err = errors.Join(err, reportErr)
+		}
 		if err != nil {
 			return err
 		}
@@ -728,6 +876,10 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 	}
 }
 
+func newEventTrigger(ctx context.Context, input *Input, eventName string, event map[string]interface{}) *model.EventTrigger {
+	return model.NewEventTrigger(ctx, eventName, event, input.Workdir(), input.defaultBranch)
+}
+
 func defaultImageSurvey(actrc string) error {
 	var answer string
 	confirmation := &survey.Select{
@@ -2,10 +2,21 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"encoding/xml"
+	"os"
 	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/runhistory"
+	"github.com/nektos/act/pkg/runner"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReadSecrets(t *testing.T) {
@@ -67,6 +78,68 @@ func TestRunPushJsonLogger(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRunChainWorkflowRuns(t *testing.T) {
+	chainLog := filepath.Join(t.TempDir(), "chain.log")
+	rootCmd := createRootCommand(context.Background(), &Input{}, "")
+	err := newRunCommand(context.Background(), &Input{
+		platforms:         []string{"ubuntu-latest=-self-hosted"},
+		workdir:           "testdata",
+		workflowsPath:     "./workflow_run",
+		envs:              []string{"CHAIN_LOG=" + chainLog},
+		chainWorkflowRuns: true,
+		noCacheServer:     true,
+	})(rootCmd, []string{"push"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(chainLog)
+	require.NoError(t, err)
+	assert.Equal(t, "build\ndeploy build push\nnotify deploy success\n", string(content))
+}
+
+func TestRerunFailedJobs(t *testing.T) {
+	dir := t.TempDir()
+	rerunLog := filepath.Join(dir, "rerun.log")
+	newInput := func() *Input {
+		return &Input{
+			platforms:     []string{"ubuntu-latest=-self-hosted"},
+			workdir:       "testdata",
+			workflowsPath: "./rerun",
+			envs:          []string{"RERUN_LOG=" + rerunLog},
+			noCacheServer: true,
+			historyPath:   filepath.Join(dir, "history"),
+		}
+	}
+
+	rootCmd := createRootCommand(context.Background(), &Input{}, "")
+	err := newRunCommand(context.Background(), newInput())(rootCmd, []string{"push"})
+	require.Error(t, err)
+
+	store, err := runhistory.Open(filepath.Join(dir, "history"))
+	require.NoError(t, err)
+	runs, err := store.List(0)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "failure", runs[0].Conclusion)
+	assert.Equal(t, map[string]string{"rerun.yml/build": "success", "rerun.yml/deploy": "failure"}, runs[0].Results())
+
+	require.NoError(t, os.WriteFile(rerunLog+".ok", []byte{}, 0o600))
+	input := newInput()
+	rerunCmd := newRerunCommand(context.Background(), input, rootCmd)
+	require.NoError(t, rerunCmd.Flags().Set("failed", "true"))
+	err = rerunCmd.RunE(rerunCmd, []string{strconv.FormatUint(runs[0].ID, 10)})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(rerunLog)
+	require.NoError(t, err)
+	assert.Equal(t, "build\ndeploy 1.2.3\ndeploy 1.2.3\n", string(content))
+
+	runs, err = store.List(0)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "success", runs[0].Conclusion)
+	assert.Equal(t, runs[1].ID, runs[0].RerunOf)
+}
+
 func TestFlags(t *testing.T) {
 	for _, f := range []string{"graph", "list", "bug-report", "man-page"} {
 		t.Run("TestFlag-"+f, func(t *testing.T) {
@@ -123,3 +196,413 @@ func TestReadArgsFile(t *testing.T) {
 		})
 	}
 }
+
+func TestReadEnvironmentEnvs(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		".secrets":                "SHARED=repo\n",
+		".secrets.production":     "token=production\n",
+		".secrets.staging":        "TOKEN=staging\n",
+		".secrets.bak":            "not a 'dotenv\n",
+		"vars.yml":                "TARGET: repo\n",
+		"vars.production.yml":     "TARGET: production\n",
+		"vars.production.old.yml": "TARGET: old\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	secrets, err := readEnvironmentEnvs(filepath.Join(dir, ".secrets"), []string{"Production"}, false, true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"production": {"TOKEN": "production"},
+	}, secrets)
+
+	secrets, err = readEnvironmentEnvs(filepath.Join(dir, ".secrets"), nil, true, true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"production": {"TOKEN": "production"},
+		"staging":    {"TOKEN": "staging"},
+	}, secrets)
+
+	_, err = readEnvironmentEnvs(filepath.Join(dir, ".secrets"), []string{"bak"}, false, true)
+	assert.ErrorContains(t, err, "unable to load the environment 'bak'")
+
+	secrets, err = readEnvironmentEnvs(filepath.Join(dir, ".secrets"), nil, false, true)
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+
+	vars, err := readEnvironmentEnvs(filepath.Join(dir, "vars.yml"), []string{"production", "staging"}, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"production": {"TARGET": "production"},
+	}, vars)
+
+	vars, err = readEnvironmentEnvs(filepath.Join(dir, ".missing"), nil, true, false)
+	require.NoError(t, err)
+	assert.Empty(t, vars)
+}
+
+func TestParseScheduleTime(t *testing.T) {
+	expected := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+	for _, value := range []string{"2024-01-01T09:30", "2024-01-01 09:30", "2024-01-01T09:30:00Z", "2024-01-01T10:30:00+01:00"} {
+		actual, err := parseScheduleTime(value)
+		require.NoError(t, err, value)
+		assert.True(t, expected.Equal(actual), value)
+	}
+	_, err := parseScheduleTime("tomorrow")
+	assert.Error(t, err)
+}
+
+func TestRunScheduleDaemonJobCancellation(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "yearly.yml"), []byte(`
+on:
+  schedule:
+    - cron: '0 0 1 1 *'
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`), 0o600))
+
+	jobCancelCtx, cancelJobs := context.WithCancel(context.Background())
+	ctx := common.WithJobCancelContext(context.Background(), jobCancelCtx)
+	done := make(chan error)
+	go func() {
+		done <- runScheduleDaemon(ctx, &Input{workflowsPath: dir}, func() error { return nil })
+	}()
+	cancelJobs()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the daemon did not stop when the jobs were cancelled")
+	}
+}
+
+func TestRunAnnotationReports(t *testing.T) {
+	dir := t.TempDir()
+	input := &Input{
+		platforms:     []string{"ubuntu-latest=-self-hosted"},
+		workdir:       "testdata",
+		workflowsPath: "./annotations",
+		noCacheServer: true,
+		secrets:       []string{"TOKEN=s3cr3t"},
+		sarifFile:     filepath.Join(dir, "act.sarif"),
+		checksFile:    filepath.Join(dir, "checks.json"),
+	}
+	rootCmd := createRootCommand(context.Background(), &Input{}, "")
+	err := newRunCommand(context.Background(), input)(rootCmd, []string{"push"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(input.sarifFile)
+	require.NoError(t, err)
+	sarif := struct {
+		Runs []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}{}
+	require.NoError(t, json.Unmarshal(content, &sarif))
+	require.Len(t, sarif.Runs, 1)
+	require.Len(t, sarif.Runs[0].Results, 3)
+	assert.Equal(t, "vet", sarif.Runs[0].Results[0].RuleID)
+	assert.Equal(t, "error", sarif.Runs[0].Results[0].Level)
+	assert.Equal(t, "main.go", sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "warning", sarif.Runs[0].Results[1].Level)
+	// the properties of the annotations are masked
+	assert.Equal(t, "***", sarif.Runs[0].Results[1].RuleID)
+	assert.Equal(t, "***/config.go", sarif.Runs[0].Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "note", sarif.Runs[0].Results[2].Level)
+
+	content, err = os.ReadFile(input.checksFile)
+	require.NoError(t, err)
+	checks := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(content, &checks))
+	assert.Equal(t, "1 error(s), 1 warning(s), 1 notice(s)", checks["summary"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"path": "main.go", "start_line": 3.0, "end_line": 3.0, "start_column": 5.0, "annotation_level": "failure", "title": "vet", "message": "unreachable code", "raw_details": "lint: vet"},
+		map[string]interface{}{"path": "***/config.go", "start_line": 1.0, "end_line": 1.0, "annotation_level": "warning", "title": "***", "message": "deprecated input", "raw_details": "lint: vet"},
+		map[string]interface{}{"path": "README.md", "start_line": 1.0, "end_line": 4.0, "annotation_level": "notice", "message": "outdated section", "raw_details": "lint: docs"},
+	}, checks["annotations"])
+}
+
+func TestRunReportJUnit(t *testing.T) {
+	input := &Input{
+		platforms:     []string{"ubuntu-latest=-self-hosted"},
+		workdir:       "testdata",
+		workflowsPath: "./junit",
+		noCacheServer: true,
+		reportJUnit:   filepath.Join(t.TempDir(), "junit.xml"),
+	}
+	rootCmd := createRootCommand(context.Background(), &Input{}, "")
+	err := newRunCommand(context.Background(), input)(rootCmd, []string{"push"})
+	require.Error(t, err)
+
+	content, err := os.ReadFile(input.reportJUnit)
+	require.NoError(t, err)
+	report := struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			Name       string `xml:"name,attr"`
+			Properties []struct {
+				Name  string `xml:"name,attr"`
+				Value string `xml:"value,attr"`
+			} `xml:"properties>property"`
+			Cases []struct {
+				Name    string `xml:"name,attr"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"failure"`
+				Skipped   *struct{} `xml:"skipped"`
+				SystemOut string    `xml:"system-out"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}{}
+	require.NoError(t, xml.Unmarshal(content, &report))
+	assert.Equal(t, 6, report.Tests)
+	assert.Equal(t, 2, report.Failures)
+	assert.Equal(t, 2, report.Skipped)
+	require.Len(t, report.Suites, 2)
+
+	shards := []string{}
+	for _, suite := range report.Suites {
+		require.Len(t, suite.Properties, 1)
+		shard := suite.Properties[0].Value
+		shards = append(shards, shard)
+		require.Len(t, suite.Cases, 3)
+		assert.Equal(t, "hello", suite.Cases[0].Name)
+		assert.Nil(t, suite.Cases[0].Failure)
+		assert.Contains(t, suite.Cases[0].SystemOut, "hello from shard "+shard)
+		assert.NotNil(t, suite.Cases[1].Skipped)
+		require.NotNil(t, suite.Cases[2].Failure)
+		assert.Equal(t, "exit status "+shard, suite.Cases[2].Failure.Message)
+		assert.Contains(t, suite.Cases[2].SystemOut, "shard "+shard+" failed")
+	}
+	assert.ElementsMatch(t, []string{"1", "2"}, shards)
+}
+
+func TestRunStepSummaryReports(t *testing.T) {
+	dir := t.TempDir()
+	input := &Input{
+		platforms:      []string{"ubuntu-latest=-self-hosted"},
+		workdir:        "testdata",
+		workflowsPath:  "./summary",
+		noCacheServer:  true,
+		reportMarkdown: filepath.Join(dir, "report.md"),
+		reportHTML:     filepath.Join(dir, "report.html"),
+	}
+	rootCmd := createRootCommand(context.Background(), &Input{}, "")
+	err := newRunCommand(context.Background(), input)(rootCmd, []string{"push"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(input.reportMarkdown)
+	require.NoError(t, err)
+	markdown := string(content)
+	assert.Contains(t, markdown, "| summary / test | ✅ success |")
+	assert.Contains(t, markdown, "| unit tests | ✅ success |")
+	assert.Contains(t, markdown, "### summary / test: unit tests\n\n### Unit tests\n\nAll **42** tests passed\n")
+	assert.NotContains(t, markdown, "summary / test: no summary")
+	// summaries over the limit of GitHub are truncated
+	assert.Contains(t, markdown, "### summary / test: oversized summary\n\naaaa")
+	assert.Contains(t, markdown, "a\n\n*The summary was truncated, its 1100000 bytes exceed the limit of 1048576 bytes.*\n")
+
+	content, err = os.ReadFile(input.reportHTML)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "<p>All <strong>42</strong> tests passed</p>")
+}
+
+func TestRunEventStream(t *testing.T) {
+	input := &Input{
+		platforms:     []string{"ubuntu-latest=-self-hosted"},
+		workdir:       "testdata",
+		workflowsPath: "./events",
+		noCacheServer: true,
+		eventStream:   filepath.Join(t.TempDir(), "events.ndjson"),
+	}
+	rootCmd := createRootCommand(context.Background(), &Input{}, "")
+	err := newRunCommand(context.Background(), input)(rootCmd, []string{"push"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(input.eventStream)
+	require.NoError(t, err)
+	events := []runner.Event{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		event := runner.Event{}
+		require.NoError(t, json.Unmarshal([]byte(line), &event), line)
+		events = append(events, event)
+	}
+
+	types := []runner.EventType{}
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []runner.EventType{
+		runner.EventPlanCreated,
+		runner.EventJobStarted,
+		runner.EventStepStarted,
+		runner.EventOutputSet,
+		runner.EventStepFinished,
+		runner.EventStepStarted,
+		runner.EventAnnotation,
+		runner.EventStepFinished,
+		runner.EventJobFinished,
+		runner.EventRunFinished,
+	}, types)
+
+	assert.Equal(t, [][]runner.PlannedJob{{{Workflow: "push.yml", JobID: "build", Name: "build"}}}, events[0].Stages)
+	assert.Equal(t, "events/build", events[1].Job)
+	assert.Equal(t, "version", events[3].StepID)
+	assert.Equal(t, "version", events[3].Name)
+	assert.Equal(t, "1.2.3", events[3].Value)
+	assert.Equal(t, "success", events[4].Outcome)
+	assert.Equal(t, &runner.Annotation{Level: "warning", Message: "unused variable", File: "main.go", Line: 3, StepID: "lint", Step: "lint"}, events[6].Annotation)
+	assert.Equal(t, "success", events[8].Result)
+	assert.Equal(t, map[string]string{"version": "1.2.3"}, events[8].Outputs)
+	assert.Equal(t, "success", events[9].Result)
+}
+
+func TestRunLogDir(t *testing.T) {
+	input := &Input{
+		platforms:     []string{"ubuntu-latest=-self-hosted"},
+		workdir:       "testdata",
+		workflowsPath: "./logdir",
+		noCacheServer: true,
+		secrets:       []string{"TOKEN=s3cr3t"},
+		logDir:        t.TempDir(),
+	}
+	// the logs of a previous run in the directory are replaced
+	for range 2 {
+		rootCmd := createRootCommand(context.Background(), &Input{}, "")
+		err := newRunCommand(context.Background(), input)(rootCmd, []string{"push"})
+		require.NoError(t, err)
+	}
+
+	files := []string{}
+	err := filepath.WalkDir(input.logDir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(input.logDir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"logs/1_test-1.txt",
+		"logs/2_test-2.txt",
+		"logs/test-1/1_Set up job.txt",
+		"logs/test-1/2_greet.txt",
+		"logs/test-1/3_secret.txt",
+		"logs/test-1/4_Complete job.txt",
+		"logs/test-2/1_Set up job.txt",
+		"logs/test-2/2_greet.txt",
+		"logs/test-2/3_secret.txt",
+		"logs/test-2/4_Complete job.txt",
+	}, files)
+
+	greet, err := os.ReadFile(filepath.Join(input.logDir, "logs", "test-1", "2_greet.txt"))
+	require.NoError(t, err)
+	assert.Regexp(t, `(?m)^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{7}Z hello 18$`, string(greet))
+	assert.NotContains(t, string(greet), "\x1b[")
+
+	secret, err := os.ReadFile(filepath.Join(input.logDir, "logs", "test-2", "3_secret.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(secret), " token ***\n")
+	assert.NotContains(t, string(secret), "s3cr3t")
+
+	job, err := os.ReadFile(filepath.Join(input.logDir, "logs", "2_test-2.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(job), "Z token ***\n")
+	assert.Contains(t, string(job), "Z hello 20\n")
+	assert.Contains(t, string(job), "Z 🏁  Job succeeded\n")
+}
+
+func TestRunStepOverrides(t *testing.T) {
+	input := &Input{
+		platforms:     []string{"ubuntu-latest=-self-hosted"},
+		workdir:       "testdata",
+		workflowsPath: "./overrides",
+		noCacheServer: true,
+		stepOverrides: path.Join("testdata", "overrides.yml"),
+		logDir:        t.TempDir(),
+	}
+	rootCmd := createRootCommand(context.Background(), &Input{}, "")
+	err := newRunCommand(context.Background(), input)(rootCmd, []string{"push"})
+	require.NoError(t, err)
+
+	job, err := os.ReadFile(filepath.Join(input.logDir, "overrides", "1_deploy.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(job), "Z 🎭  Skip notify (overridden: skip)\n")
+	assert.Contains(t, string(job), "Z ⭐ Run Main example/deploy-action@v2 (overridden: outputs)\n")
+	assert.Contains(t, string(job), "Z   ⚙  ::set-output:: url=https://example.com/push\n")
+	assert.Contains(t, string(job), "Z ⭐ Run Main exit 1 (overridden: run)\n")
+	assert.Contains(t, string(job), "Z   ✅  Success - Main check")
+}
+
+func TestRunStepOverridesInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "overrides.yml")
+	require.NoError(t, os.WriteFile(file, []byte("steps:\n  - id: deploy\n    skip: true\n    run: echo\n"), 0o600))
+	input := &Input{
+		platforms:     []string{"ubuntu-latest=-self-hosted"},
+		workdir:       "testdata",
+		workflowsPath: "./overrides",
+		noCacheServer: true,
+		stepOverrides: file,
+	}
+	rootCmd := createRootCommand(context.Background(), &Input{}, "")
+	err := newRunCommand(context.Background(), input)(rootCmd, []string{"push"})
+	assert.ErrorContains(t, err, "invalid step override 1")
+}
+
+func TestTestCommand(t *testing.T) {
+	input := &Input{
+		platforms:     []string{"ubuntu-latest=-self-hosted"},
+		workdir:       "testdata",
+		workflowsPath: "./workflowtest",
+		noCacheServer: true,
+		reportJUnit:   filepath.Join(t.TempDir(), "junit.xml"),
+	}
+	rootCmd := createRootCommand(context.Background(), &Input{}, "")
+	testCmd := newTestCommand(context.Background(), input, rootCmd)
+	err := testCmd.RunE(testCmd, []string{path.Join("testdata", "act-tests")})
+	assert.EqualError(t, err, "1 of 2 test cases failed")
+
+	junit, err := os.ReadFile(input.reportJUnit)
+	require.NoError(t, err)
+	assert.Contains(t, string(junit), `<testsuites name="act test" tests="2" failures="1"`)
+	assert.Contains(t, string(junit), `<testcase name="build greets and deploys" classname="testdata/act-tests/build.yml"`)
+	assert.Contains(t, string(junit), `<failure message="expected the run to end with success, got failure: `)
+	assert.Contains(t, string(junit), "job build: expected result success, got failure")
+	assert.Contains(t, string(junit), "the run: the log does not contain &#34;deployed to&#34;")
+}
+
+func TestTestCommandJobFlag(t *testing.T) {
+	input := &Input{
+		platforms:     []string{"ubuntu-latest=-self-hosted"},
+		workdir:       "testdata",
+		workflowsPath: "./workflowtest",
+		noCacheServer: true,
+	}
+	rootCmd := createRootCommand(context.Background(), &Input{}, "")
+	testCmd := newTestCommand(context.Background(), input, rootCmd)
+	require.NoError(t, testCmd.Flags().Set("job", "build"))
+	// the test cases without a job run the job of --job
+	err := testCmd.RunE(testCmd, []string{path.Join("testdata", "act-tests")})
+	assert.EqualError(t, err, "1 of 2 test cases failed")
+	job, err := testCmd.Flags().GetString("job")
+	require.NoError(t, err)
+	assert.Equal(t, "build", job)
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nektos/act/pkg/runhistory"
+	"github.com/nektos/act/pkg/runner"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func newRunsCommand(input *Input) *cobra.Command {
+	runsCmd := &cobra.Command{
+		Use:   "runs",
+		Short: "Inspect the history of the runs",
+		Args:  cobra.NoArgs,
+	}
+
+	var limit int
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the most recent runs",
+		Args:    cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			store, err := runhistory.Open(input.historyPath)
+			if err != nil {
+				return err
+			}
+			runs, err := store.List(limit)
+			if err != nil {
+				return err
+			}
+			printRuns(os.Stdout, runs)
+			return nil
+		},
+		// flags of the .actrc are meant for the run command
+		FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	}
+	listCmd.Flags().IntVar(&limit, "limit", 20, "number of runs to list, 0 lists all runs of the history")
+
+	var logs bool
+	showCmd := &cobra.Command{
+		Use:   "show <run>",
+		Short: "Show the jobs, steps and outputs of a run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			run, err := getRun(input, args[0])
+			if err != nil {
+				return err
+			}
+			printRun(os.Stdout, run, logs)
+			return nil
+		},
+		FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	}
+	showCmd.Flags().BoolVar(&logs, "logs", false, "print the logs of the jobs")
+
+	runsCmd.AddCommand(listCmd, showCmd)
+	return runsCmd
+}
+
+func newRerunCommand(ctx context.Context, input *Input, rootCmd *cobra.Command) *cobra.Command {
+	var failed bool
+	rerunCmd := &cobra.Command{
+		Use:   "rerun <run>",
+		Short: "Run the jobs of a run of the history again",
+		Long:  "Run the jobs of a run of the history again, with the same event, workflows and inputs. With --failed only the failed jobs and the jobs which need them run again, the other jobs keep their results and outputs.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			run, err := getRun(input, args[0])
+			if err != nil {
+				return err
+			}
+			input.rerun = run
+			input.rerunFailed = failed
+			input.workdir = run.Workdir
+			input.workflowsPath = run.WorkflowsPath
+			input.eventPath = ""
+			input.generateEvent = false
+			input.inputs = []string{}
+			for k, v := range run.Inputs {
+				input.inputs = append(input.inputs, fmt.Sprintf("%s=%s", k, v))
+			}
+			return newRunCommand(ctx, input)(cmd, []string{run.EventName})
+		},
+	}
+	// the rerun command runs workflows like the root command and accepts the same flags
+	rerunCmd.Flags().AddFlagSet(rootCmd.Flags())
+	rerunCmd.Flags().BoolVar(&failed, "failed", false, "run only the failed jobs and the jobs which need them again")
+	return rerunCmd
+}
+
+func getRun(input *Input, id string) (*runhistory.Run, error) {
+	runID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run '%s', expected the ID of a run listed by `act runs list`", id)
+	}
+	store, err := runhistory.Open(input.historyPath)
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(runID)
+}
+
+// recordRun starts the record of a run in the history, nil if the history is disabled
+func recordRun(input *Input, run *runhistory.Run, config *runner.Config) *runhistory.Recorder {
+	if input.noHistory || input.historyPath == "" || input.dryrun {
+		return nil
+	}
+	if config.EventPath != "" {
+		if content, err := os.ReadFile(config.EventPath); err == nil {
+			run.EventJSON = string(content)
+		}
+	}
+	if input.rerun != nil {
+		run.RerunOf = input.rerun.ID
+	}
+	recorder := runhistory.NewRecorder(run)
+	config.RecordJob = recorder.RecordJob
+	return recorder
+}
+
+// saveRun adds a completed run to the history, a failure to save it does not fail the run
+func saveRun(input *Input, run *runhistory.Run) {
+	store, err := runhistory.Open(input.historyPath)
+	if err == nil {
+		err = store.Save(run)
+	}
+	if err != nil {
+		log.Warnf("Unable to save the run to the history: %v", err)
+		return
+	}
+	log.Infof("Run %d saved to the history, see `act runs show %d`", run.ID, run.ID)
+}
+
+func printRuns(w io.Writer, runs []*runhistory.Run) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tStarted\tDuration\tEvent\tConclusion\tWorkflows")
+	for _, run := range runs {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", run.ID, formatUnix(run.StartedAt), runDuration(run), run.EventName, run.Conclusion, strings.Join(runWorkflows(run), ", "))
+	}
+	_ = tw.Flush()
+}
+
+func printRun(w io.Writer, run *runhistory.Run, logs bool) {
+	fmt.Fprintf(w, "Run %d: %s\n", run.ID, run.Conclusion)
+	fmt.Fprintf(w, "  Event:     %s\n", run.EventName)
+	fmt.Fprintf(w, "  Workflows: %s\n", strings.Join(runWorkflows(run), ", "))
+	fmt.Fprintf(w, "  Started:   %s (%s)\n", formatUnix(run.StartedAt), runDuration(run))
+	if run.RerunOf != 0 {
+		fmt.Fprintf(w, "  Rerun of:  %d\n", run.RerunOf)
+	}
+	if run.Error != "" {
+		fmt.Fprintf(w, "  Error:     %s\n", run.Error)
+	}
+
+	for _, job := range run.Jobs {
+		fmt.Fprintf(w, "\nJob %s (%s/%s): %s", job.Name, job.Workflow, job.JobID, job.Result)
+		if job.Reused {
+			fmt.Fprint(w, ", reused from a previous run")
+		}
+		fmt.Fprintln(w)
+		if len(job.Matrix) > 0 {
+			fmt.Fprintf(w, "  Matrix: %v\n", job.Matrix)
+		}
+		if job.EnvironmentURL != "" {
+			fmt.Fprintf(w, "  Environment: %s (%s)\n", job.Environment, job.EnvironmentURL)
+		} else if job.Environment != "" {
+			fmt.Fprintf(w, "  Environment: %s\n", job.Environment)
+		}
+		for _, step := range job.Steps {
+			name, _, _ := strings.Cut(step.Name, "\n")
+			fmt.Fprintf(w, "  Step %s: %s\n", name, step.Conclusion)
+			printOutputs(w, "    ", step.Outputs)
+		}
+		if len(job.Outputs) > 0 {
+			fmt.Fprintln(w, "  Outputs:")
+			printOutputs(w, "    ", job.Outputs)
+		}
+		if logs && job.Log != "" {
+			fmt.Fprintln(w, "  Log:")
+			for _, line := range strings.Split(strings.TrimSuffix(job.Log, "\n"), "\n") {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+	}
+}
+
+func printOutputs(w io.Writer, indent string, outputs map[string]string) {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s%s=%s\n", indent, name, outputs[name])
+	}
+}
+
+func runWorkflows(run *runhistory.Run) []string {
+	workflows := []string{}
+	for _, stage := range run.Stages {
+		for _, job := range stage {
+			if !slices.Contains(workflows, job.Workflow) {
+				workflows = append(workflows, job.Workflow)
+			}
+		}
+	}
+	return workflows
+}
+
+func runDuration(run *runhistory.Run) time.Duration {
+	if run.FinishedAt < run.StartedAt {
+		return 0
+	}
+	return time.Duration(run.FinishedAt-run.StartedAt) * time.Second
+}
+
+func formatUnix(t int64) string {
+	return time.Unix(t, 0).Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// scheduleTimeLayouts are the accepted formats of --at, times without a zone are in UTC like the cron expressions of GitHub
+var scheduleTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04"}
+
+func parseScheduleTime(value string) (time.Time, error) {
+	for _, layout := range scheduleTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%s', expected a time like 2006-01-02T15:04 (UTC) or in RFC 3339", value)
+}
+
+func newScheduleCommand(ctx context.Context, input *Input, rootCmd *cobra.Command) *cobra.Command {
+	var at string
+	var daemon bool
+	var upcoming int
+
+	scheduleCmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Run the workflows triggered by the schedule event",
+		Long:  "Run the workflows triggered by the schedule event. Without flags all scheduled workflows run, with --at only the workflows whose cron expressions fire at the given time. With --daemon act keeps running and triggers the workflows when their cron expressions fire.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			run := newRunCommand(ctx, input)
+			switch {
+			case upcoming > 0:
+				planner, err := model.NewWorkflowPlanner(input.WorkflowsPath(), input.noWorkflowRecurse, input.strict)
+				if err != nil {
+					return err
+				}
+				from := time.Now()
+				if at != "" {
+					if from, err = parseScheduleTime(at); err != nil {
+						return err
+					}
+				}
+				runs, err := planner.GetScheduledRuns(from, upcoming)
+				printScheduledRuns(os.Stdout, runs)
+				return err
+			case daemon:
+				return runScheduleDaemon(ctx, input, func() error {
+					return run(cmd, []string{"schedule"})
+				})
+			case at != "":
+				t, err := parseScheduleTime(at)
+				if err != nil {
+					return err
+				}
+				input.scheduleAt = t
+			}
+			return run(cmd, []string{"schedule"})
+		},
+	}
+	// the schedule command runs workflows like the root command and accepts the same flags
+	scheduleCmd.Flags().AddFlagSet(rootCmd.Flags())
+	scheduleCmd.Flags().StringVar(&at, "at", "", "simulated time, only the workflows whose schedules fire at this time run (e.g. --at 2024-01-01T09:00)")
+	scheduleCmd.Flags().BoolVar(&daemon, "daemon", false, "keep running and trigger the scheduled workflows when their cron expressions fire")
+	scheduleCmd.Flags().IntVar(&upcoming, "upcoming", 0, "list the given number of upcoming scheduled runs instead of running workflows")
+	return scheduleCmd
+}
+
+func printScheduledRuns(w io.Writer, runs []model.ScheduledRun) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Time (UTC)\tWorkflow name\tWorkflow file\tCron")
+	for _, run := range runs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", run.Time.UTC().Format("2006-01-02 15:04"), run.Workflow.Name, run.Workflow.File, run.Cron)
+	}
+	_ = tw.Flush()
+}
+
+// runScheduleDaemon runs the scheduled workflows whenever their schedules fire, until the context or the jobs are cancelled
+func runScheduleDaemon(ctx context.Context, input *Input, run func() error) error {
+	// the first Ctrl-C only cancels the jobs, which stops the daemon as well
+	if jobCancelCtx := common.JobCancelContext(ctx); jobCancelCtx != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		defer context.AfterFunc(jobCancelCtx, cancel)()
+	}
+	for {
+		// the workflows are reloaded to pick up changes of their schedules
+		planner, err := model.NewWorkflowPlanner(input.WorkflowsPath(), input.noWorkflowRecurse, input.strict)
+		if err != nil {
+			return err
+		}
+		runs, err := planner.GetScheduledRuns(time.Now(), 1)
+		if err != nil {
+			log.Warn(err)
+		}
+		if len(runs) == 0 {
+			return fmt.Errorf("no scheduled workflows found")
+		}
+
+		next := runs[0].Time
+		log.Infof("Next scheduled run at %s: %s (%s)", next.UTC().Format(time.RFC3339), runs[0].Workflow.Name, runs[0].Cron)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		input.scheduleAt = next
+		if err := run(); err != nil {
+			log.Errorf("Scheduled run at %s failed: %v", next.UTC().Format(time.RFC3339), err)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/workflowtest"
+)
+
+func newTestCommand(ctx context.Context, input *Input, rootCmd *cobra.Command) *cobra.Command {
+	testCmd := &cobra.Command{
+		Use:   "test [path...]",
+		Short: "Run the test cases of workflows and check their results",
+		Long:  "Run the test cases of workflows and check their results. A test case triggers an event with a payload, inputs, secrets and mocked steps, and checks the results and outputs of the jobs and steps, the uploaded artifacts and the logs. The test cases are read from the given files and directories, by default from .github/act-tests. With --report-junit the results are written as JUnit XML.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{filepath.Join(input.Workdir(), ".github", "act-tests")}
+			}
+			cases, err := workflowtest.ReadCases(args...)
+			if err != nil {
+				return err
+			}
+			if len(cases) == 0 {
+				return fmt.Errorf("no test cases found in %v", args)
+			}
+
+			tempDir, err := os.MkdirTemp("", "act-test")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(tempDir)
+
+			// the job of a test case replaces the job of --job for that case only
+			job, err := cmd.Flags().GetString("job")
+			if err != nil {
+				return err
+			}
+			results := []*workflowtest.Result{}
+			for i, testCase := range cases {
+				caseInput, err := newTestCaseInput(input, testCase, filepath.Join(tempDir, fmt.Sprintf("case-%d", i+1)))
+				if err != nil {
+					return err
+				}
+				caseJob := job
+				if testCase.Job != "" {
+					caseJob = testCase.Job
+				}
+				if err := cmd.Flags().Set("job", caseJob); err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stdout, "=== RUN   %s\n", testCase.Name)
+				err = newRunCommand(ctx, caseInput)(cmd, []string{testCase.Event})
+				results = append(results, caseInput.testRun.Finish(err))
+			}
+
+			fmt.Println()
+			workflowtest.WriteResults(os.Stdout, results)
+			if input.reportJUnit != "" {
+				if err := writeReportFile(input.reportJUnit, func(w io.Writer) error { return workflowtest.WriteJUnit(w, results) }); err != nil {
+					return err
+				}
+			}
+
+			failed := 0
+			for _, result := range results {
+				if !result.Passed() {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d test cases failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+	// the test command runs workflows like the root command and accepts the same flags
+	testCmd.Flags().AddFlagSet(rootCmd.Flags())
+	return testCmd
+}
+
+// newTestCaseInput returns the input of the run of a test case, the event payload and the uploaded artifacts
+// of the case are kept in its own directory
+func newTestCaseInput(input *Input, testCase *workflowtest.Case, dir string) (*Input, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	caseInput := *input
+	caseInput.testRun = workflowtest.NewRun(testCase)
+	caseInput.noHistory = true
+	caseInput.reportJUnit = ""
+
+	payload := map[string]interface{}{}
+	for k, v := range testCase.Payload {
+		payload[k] = v
+	}
+	if _, ok := payload["inputs"]; !ok && testCase.Event == "workflow_dispatch" && len(testCase.Inputs) > 0 {
+		payload["inputs"] = testCase.Inputs
+	}
+	eventJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	caseInput.eventPath = filepath.Join(dir, "event.json")
+	if err := os.WriteFile(caseInput.eventPath, eventJSON, 0o600); err != nil {
+		return nil, err
+	}
+
+	caseInput.inputs = appendKeyValues(input.inputs, testCase.Inputs)
+	caseInput.secrets = appendKeyValues(input.secrets, testCase.Secrets)
+	caseInput.vars = appendKeyValues(input.vars, testCase.Vars)
+	caseInput.envs = appendKeyValues(input.envs, testCase.Env)
+	caseInput.matrix = append(append([]string{}, input.matrix...), testCase.MatrixArgs()...)
+	if testCase.Workflow != "" {
+		caseInput.workflowsPath = filepath.Join(input.workflowsPath, testCase.Workflow)
+	}
+	if len(testCase.Expect.Artifacts) > 0 && caseInput.artifactServerPath == "" {
+		caseInput.artifactServerPath = filepath.Join(dir, "artifacts")
+	}
+	return &caseInput, nil
+}
+
+func appendKeyValues(values []string, m map[string]string) []string {
+	values = append([]string{}, values...)
+	for k, v := range m {
+		values = append(values, fmt.Sprintf("%s=%s", k, v))
+	}
+	return values
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxWorkflowRunDepth is the number of levels of workflows GitHub chains with workflow_run events
+// https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows#workflow_run
+const maxWorkflowRunDepth = 3
+
+type completedWorkflow struct {
+	run       model.WorkflowRun
+	eventName string
+	event     map[string]interface{}
+}
+
+// chainWorkflowRuns runs the plan, then the workflows triggered by the workflow_run events of its completed workflows
+// and of the workflows these trigger in turn, until no more workflows are triggered
+func chainWorkflowRuns(input *Input, config runner.Config, plan *model.Plan, event map[string]interface{}, executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		errs := []error{executor(ctx)}
+		completed := completedWorkflows(plan, config.EventName, event)
+
+		for depth := 1; len(completed) > 0 && ctx.Err() == nil; depth++ {
+			if depth > maxWorkflowRunDepth {
+				for _, c := range completed {
+					log.Warnf("Not running the workflow_run workflows of '%s', workflows are chained at most %d levels deep", c.run.Workflow.Name, maxWorkflowRunDepth)
+				}
+				break
+			}
+
+			next := []completedWorkflow{}
+			for _, c := range completed {
+				payload := model.NewWorkflowRunEvent(ctx, c.run, c.eventName, c.event, input.Workdir())
+				// the workflows are reloaded, the job results of a plan are stored in its workflows
+				planner, err := model.NewWorkflowPlanner(input.WorkflowsPath(), input.noWorkflowRecurse, input.strict)
+				if err != nil {
+					errs = append(errs, err)
+					break
+				}
+				downstream, err := planner.PlanTrigger(newEventTrigger(ctx, input, "workflow_run", payload))
+				if err != nil {
+					errs = append(errs, err)
+				}
+				if downstream == nil || len(downstream.Stages) == 0 {
+					continue
+				}
+
+				log.Infof("Workflow '%s' completed with %s, running the workflows triggered by workflow_run", c.run.Workflow.Name, c.run.Conclusion)
+				eventJSON, err := json.Marshal(payload)
+				if err != nil {
+					return err
+				}
+				chained := config
+				chained.EventName = "workflow_run"
+				chained.EventPath = ""
+				chained.EventJSON = string(eventJSON)
+				if chained.EnvironmentSecrets, chained.EnvironmentVars, err = loadEnvironments(input, downstream); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				r, err := runner.New(&chained)
+				if err != nil {
+					return err
+				}
+				errs = append(errs, r.NewPlanExecutor(downstream)(ctx))
+				next = append(next, completedWorkflows(downstream, "workflow_run", payload)...)
+			}
+			completed = next
+		}
+		return errors.Join(errs...)
+	}
+}
+
+func completedWorkflows(plan *model.Plan, eventName string, event map[string]interface{}) []completedWorkflow {
+	completed := []completedWorkflow{}
+	for _, run := range plan.WorkflowRuns() {
+		completed = append(completed, completedWorkflow{run, eventName, event})
+	}
+	return completed
+}
@@ -108,11 +108,12 @@ const (
 )
 
 type artifactV4Routes struct {
-	prefix  string
-	fs      WriteFS
-	rfs     fs.FS
-	AppURL  string
-	baseDir string
+	prefix   string
+	fs       WriteFS
+	rfs      fs.FS
+	AppURL   string
+	baseDir  string
+	onUpload UploadHandler
 }
 
 type ArtifactContext struct {
@@ -145,11 +146,16 @@ func validateRunIDV4(ctx *ArtifactContext, rawRunID string) (interface{}, int64,
 }
 
 func RoutesV4(router *httprouter.Router, baseDir string, fsys WriteFS, rfs fs.FS) {
+	routesV4(router, baseDir, fsys, rfs, nil)
+}
+
+func routesV4(router *httprouter.Router, baseDir string, fsys WriteFS, rfs fs.FS, onUpload UploadHandler) {
 	route := &artifactV4Routes{
-		fs:      fsys,
-		rfs:     rfs,
-		baseDir: baseDir,
-		prefix:  ArtifactV4RouteBase,
+		fs:       fsys,
+		rfs:      rfs,
+		baseDir:  baseDir,
+		prefix:   ArtifactV4RouteBase,
+		onUpload: onUpload,
 	}
 	router.POST(path.Join(ArtifactV4RouteBase, "CreateArtifact"), func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		route.AppURL = r.Host
@@ -342,10 +348,13 @@ func (r *artifactV4Routes) finalizeArtifact(ctx *ArtifactContext) {
 	if ok := r.parseProtbufBody(ctx, &req); !ok {
 		return
 	}
-	_, _, ok := validateRunIDV4(ctx, req.WorkflowRunBackendId)
+	_, runID, ok := validateRunIDV4(ctx, req.WorkflowRunBackendId)
 	if !ok {
 		return
 	}
+	if r.onUpload != nil {
+		r.onUpload(strconv.FormatInt(runID, 10), req.Name, req.Size)
+	}
 
 	respData := FinalizeArtifactResponse{
 		Ok:         true,
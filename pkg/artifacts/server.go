@@ -58,6 +58,28 @@ type WriteFS interface {
 type readWriteFSImpl struct {
 }
 
+// UploadHandler is called once the upload of an artifact completed
+type UploadHandler func(runID string, name string, size int64)
+
+type uploadHandlerContextKey struct{}
+
+// WithUploadHandler adds a handler which Serve calls for every uploaded artifact, after the handlers added before
+func WithUploadHandler(ctx context.Context, handler UploadHandler) context.Context {
+	if previous := uploadHandler(ctx); previous != nil {
+		next := handler
+		handler = func(runID string, name string, size int64) {
+			previous(runID, name, size)
+			next(runID, name, size)
+		}
+	}
+	return context.WithValue(ctx, uploadHandlerContextKey{}, handler)
+}
+
+func uploadHandler(ctx context.Context) UploadHandler {
+	handler, _ := ctx.Value(uploadHandlerContextKey{}).(UploadHandler)
+	return handler
+}
+
 func (fwfs readWriteFSImpl) Open(name string) (fs.File, error) {
 	return os.Open(name)
 }
@@ -92,7 +114,7 @@ func safeResolve(baseDir string, relPath string) string {
 	return filepath.Join(baseDir, filepath.Clean(filepath.Join(string(os.PathSeparator), relPath)))
 }
 
-func uploads(router *httprouter.Router, baseDir string, fsys WriteFS) {
+func uploads(router *httprouter.Router, baseDir string, fsys WriteFS, onUpload UploadHandler) {
 	router.POST("/_apis/pipelines/workflows/:runId/artifacts", func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
 		runID := params.ByName("runId")
 
@@ -160,7 +182,18 @@ func uploads(router *httprouter.Router, baseDir string, fsys WriteFS) {
 		}
 	})
 
-	router.PATCH("/_apis/pipelines/workflows/:runId/artifacts", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	router.PATCH("/_apis/pipelines/workflows/:runId/artifacts", func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		if onUpload != nil {
+			// the size of the artifact is sent once all its files are uploaded
+			var body struct {
+				Size int64
+			}
+			if req.Body != nil {
+				_ = json.NewDecoder(req.Body).Decode(&body)
+			}
+			onUpload(params.ByName("runId"), req.URL.Query().Get("artifactName"), body.Size)
+		}
+
 		json, err := json.Marshal(ResponseMessage{
 			Message: "success",
 		})
@@ -287,9 +320,9 @@ func Serve(ctx context.Context, artifactPath string, addr string, port string) c
 
 	logger.Debugf("Artifacts base path '%s'", artifactPath)
 	fsys := readWriteFSImpl{}
-	uploads(router, artifactPath, fsys)
+	uploads(router, artifactPath, fsys, uploadHandler(ctx))
 	downloads(router, artifactPath, fsys)
-	RoutesV4(router, artifactPath, fsys, fsys)
+	routesV4(router, artifactPath, fsys, fsys, uploadHandler(ctx))
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf("%s:%s", addr, port),
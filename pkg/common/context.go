@@ -2,24 +2,48 @@ package common
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
+type forceCancelCtx string
+
+const forceCancelCtxVal = forceCancelCtx("force.cancel")
+
+type interruptCtx string
+
+const interruptCtxVal = interruptCtx("interrupt")
+
+// ErrRunCancelled is the cause of the cancellation of the jobs by Ctrl-C
+var ErrRunCancelled = errors.New("the run was cancelled")
+
 func createGracefulJobCancellationContext() (context.Context, func(), chan os.Signal) {
 	ctx := context.Background()
 	ctx, forceCancel := context.WithCancel(ctx)
-	cancelCtx, cancel := context.WithCancel(ctx)
+	cancelCtx, cancelCause := context.WithCancelCause(ctx)
+	cancel := func() { cancelCause(ErrRunCancelled) }
 	ctx = WithJobCancelContext(ctx, cancelCtx)
+	ctx = withForceCancelContext(ctx, ctx)
 
 	// trap Ctrl+C and call cancel on the context
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	ctx = context.WithValue(ctx, interruptCtxVal, func() {
+		select {
+		case c <- os.Interrupt:
+		default:
+		}
+	})
 	go func() {
 		select {
 		case sig := <-c:
 			if sig == os.Interrupt {
+				logrus.Warn("Cancelling the run, the running steps are stopped and the cleanup and post steps are run. Press Ctrl-C again to stop right away")
 				cancel()
 				select {
 				case <-c:
@@ -39,7 +63,64 @@ func createGracefulJobCancellationContext() (context.Context, func(), chan os.Si
 	}, c
 }
 
+// CreateGracefulJobCancellationContext returns a context which is cancelled in two phases: the first Ctrl-C
+// cancels the job cancellation context, so the running steps are stopped while the steps using always() or cancelled()
+// and the post steps still run, the second Ctrl-C or a SIGTERM cancels the context itself
 func CreateGracefulJobCancellationContext() (context.Context, func()) {
 	ctx, cancel, _ := createGracefulJobCancellationContext()
 	return ctx, cancel
 }
+
+// Interrupt cancels the run like a Ctrl-C, e.g. when the terminal is in raw mode and Ctrl-C does not raise SIGINT:
+// the first call cancels the jobs gracefully, the second one stops the run right away.
+// It returns false if the context was not created by CreateGracefulJobCancellationContext.
+func Interrupt(ctx context.Context) bool {
+	interrupt, ok := ctx.Value(interruptCtxVal).(func())
+	if ok {
+		interrupt()
+	}
+	return ok
+}
+
+// WithCancelTimeout cancels the context once the timeout elapsed after a graceful cancellation of the jobs,
+// so the cleanup and post steps of a cancelled run can not hold it up forever
+func WithCancelTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	jobCancelCtx := JobCancelContext(ctx)
+	ctx, forceCancel := context.WithCancel(ctx)
+	if jobCancelCtx == nil || timeout <= 0 {
+		return ctx, forceCancel
+	}
+	ctx = withForceCancelContext(ctx, ctx)
+	go func() {
+		select {
+		case <-jobCancelCtx.Done():
+			select {
+			case <-time.After(timeout):
+				logrus.Warnf("The run did not stop within %v after it was cancelled, stopping it right away", timeout)
+				forceCancel()
+			case <-ctx.Done():
+			}
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, forceCancel
+}
+
+func withForceCancelContext(ctx context.Context, forceCancelCtx context.Context) context.Context {
+	return context.WithValue(ctx, forceCancelCtxVal, forceCancelCtx)
+}
+
+// ForceCancelContext returns the context which is cancelled when a cancelled run has to stop right away, if any.
+// Once it is cancelled, the processes of the stopped steps are killed and the post steps are skipped.
+func ForceCancelContext(ctx context.Context) context.Context {
+	if val, ok := ctx.Value(forceCancelCtxVal).(context.Context); ok {
+		return val
+	}
+	return nil
+}
+
+// IsForceCancelled reports whether the run was stopped right away, e.g. by a second Ctrl-C
+func IsForceCancelled(ctx context.Context) bool {
+	forceCancelCtx := ForceCancelContext(ctx)
+	return forceCancelCtx != nil && forceCancelCtx.Err() != nil
+}
@@ -20,6 +20,7 @@ func TestGracefulJobCancellationViaSigint(t *testing.T) {
 	assert.NotNil(t, cancelCtx)
 	assert.NoError(t, ctx.Err())
 	assert.NoError(t, cancelCtx.Err())
+	assert.NotNil(t, ForceCancelContext(ctx))
 	channel <- os.Interrupt
 	select {
 	case <-time.After(1 * time.Second):
@@ -40,6 +41,7 @@ func TestGracefulJobCancellationViaSigint(t *testing.T) {
 	if assert.Error(t, ctx.Err(), "context canceled") {
 		assert.Equal(t, context.Canceled, ctx.Err())
 	}
+	assert.True(t, IsForceCancelled(ctx))
 }
 
 func TestForceCancellationViaSigterm(t *testing.T) {
@@ -96,3 +98,56 @@ func TestCreateGracefulJobCancellationContextCancelFunc(t *testing.T) {
 		assert.Equal(t, context.Canceled, cancelCtx.Err())
 	}
 }
+
+func TestWithCancelTimeout(t *testing.T) {
+	jobCancelCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
+	ctx, cancel := WithCancelTimeout(WithJobCancelContext(context.Background(), jobCancelCtx), 50*time.Millisecond)
+	defer cancel()
+	assert.False(t, IsForceCancelled(ctx))
+
+	time.Sleep(100 * time.Millisecond)
+	assert.NoError(t, ctx.Err(), "the timeout starts once the jobs are cancelled")
+
+	cancelJobs()
+	select {
+	case <-time.After(1 * time.Second):
+		t.Fatal("context not canceled")
+	case <-ctx.Done():
+	}
+	assert.True(t, IsForceCancelled(ctx))
+}
+
+func TestWithCancelTimeoutWithoutJobCancelContext(t *testing.T) {
+	ctx, cancel := WithCancelTimeout(context.Background(), time.Millisecond)
+	assert.Nil(t, ForceCancelContext(ctx))
+	assert.NoError(t, ctx.Err())
+	cancel()
+	assert.Error(t, ctx.Err())
+	assert.False(t, IsForceCancelled(ctx))
+}
+
+func TestGracefulJobCancellationViaInterrupt(t *testing.T) {
+	ctx, cancel, _ := createGracefulJobCancellationContext()
+	defer cancel()
+	cancelCtx := JobCancelContext(ctx)
+
+	assert.True(t, Interrupt(ctx))
+	select {
+	case <-time.After(1 * time.Second):
+		t.Fatal("context not canceled")
+	case <-cancelCtx.Done():
+	}
+	assert.Equal(t, ErrRunCancelled, context.Cause(cancelCtx))
+	assert.NoError(t, ctx.Err())
+
+	assert.True(t, Interrupt(ctx))
+	select {
+	case <-time.After(1 * time.Second):
+		t.Fatal("context not canceled")
+	case <-ctx.Done():
+	}
+	assert.True(t, IsForceCancelled(ctx))
+
+	assert.False(t, Interrupt(context.Background()))
+}
@@ -8,12 +8,16 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/mattn/go-isatty"
@@ -160,6 +164,220 @@ func FindGitRef(ctx context.Context, file string) (string, error) {
 	return "", fmt.Errorf("failed to identify reference (tag/branch) for the checked-out revision '%s'", ref)
 }
 
+// FindChangedFiles get the files changed between the merge base of base and head, and head.
+// An empty head resolves to HEAD and additionally includes the uncommitted changes of the worktree,
+// an empty base compares head against its first parent.
+func FindChangedFiles(ctx context.Context, file, base, head string) ([]string, error) {
+	logger := common.Logger(ctx)
+
+	repo, err := git.PlainOpenWithOptions(
+		file,
+		&git.PlainOpenOptions{
+			DetectDotGit:          true,
+			EnableDotGitCommonDir: true,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	headRev := head
+	if headRev == "" {
+		headRev = string(plumbing.HEAD)
+	}
+	headCommit, err := resolveCommit(repo, headRev)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseCommit *object.Commit
+	if base == "" {
+		if headCommit.NumParents() > 0 {
+			if baseCommit, err = headCommit.Parent(0); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		other, err := resolveCommit(repo, base)
+		if err != nil {
+			return nil, err
+		}
+		bases, err := other.MergeBase(headCommit)
+		if err != nil {
+			return nil, err
+		}
+		if len(bases) > 0 {
+			baseCommit = bases[0]
+		}
+	}
+
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	var baseTree *object.Tree
+	if baseCommit != nil {
+		if baseTree, err = baseCommit.Tree(); err != nil {
+			return nil, err
+		}
+		logger.Debugf("Comparing %s with %s", baseCommit.Hash, headCommit.Hash)
+	}
+
+	changes, err := object.DiffTreeWithOptions(ctx, baseTree, headTree, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]bool{}
+	for _, change := range changes {
+		if change.From.Name != "" {
+			files[change.From.Name] = true
+		}
+		if change.To.Name != "" {
+			files[change.To.Name] = true
+		}
+	}
+
+	if head == "" {
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return nil, err
+		}
+		status, err := worktree.Status()
+		if err != nil {
+			return nil, err
+		}
+		for name, s := range status {
+			if s.Staging != git.Unmodified || s.Worktree != git.Unmodified {
+				files[name] = true
+			}
+		}
+	}
+
+	rtn := make([]string, 0, len(files))
+	for name := range files {
+		rtn = append(rtn, name)
+	}
+	sort.Strings(rtn)
+
+	logger.Debugf("Found %d changed files", len(rtn))
+	return rtn, nil
+}
+
+// Commit describes a commit of the local repository
+type Commit struct {
+	SHA            string
+	Message        string
+	AuthorName     string
+	AuthorEmail    string
+	CommitterName  string
+	CommitterEmail string
+	Timestamp      time.Time
+}
+
+// FindCommits get the commits reachable from head but not from base, oldest first.
+// An empty head resolves to HEAD, an empty base returns only the head commit.
+func FindCommits(ctx context.Context, file, base, head string) ([]Commit, error) {
+	logger := common.Logger(ctx)
+
+	repo, err := git.PlainOpenWithOptions(
+		file,
+		&git.PlainOpenOptions{
+			DetectDotGit:          true,
+			EnableDotGitCommonDir: true,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if head == "" {
+		head = string(plumbing.HEAD)
+	}
+	headCommit, err := resolveCommit(repo, head)
+	if err != nil {
+		return nil, err
+	}
+	if base == "" {
+		return []Commit{newCommit(headCommit)}, nil
+	}
+
+	baseCommit, err := resolveCommit(repo, base)
+	if err != nil {
+		return nil, err
+	}
+	excluded := map[plumbing.Hash]bool{}
+	if err := object.NewCommitPreorderIter(baseCommit, nil, nil).ForEach(func(c *object.Commit) error {
+		excluded[c.Hash] = true
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	commits := []Commit{}
+	if err := object.NewCommitPreorderIter(headCommit, excluded, nil).ForEach(func(c *object.Commit) error {
+		commits = append(commits, newCommit(c))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	// the iterator visits the children before their parents
+	slices.Reverse(commits)
+
+	logger.Debugf("Found %d commits between %s and %s", len(commits), base, head)
+	return commits, nil
+}
+
+func newCommit(c *object.Commit) Commit {
+	return Commit{
+		SHA:            c.Hash.String(),
+		Message:        c.Message,
+		AuthorName:     c.Author.Name,
+		AuthorEmail:    c.Author.Email,
+		CommitterName:  c.Committer.Name,
+		CommitterEmail: c.Committer.Email,
+		Timestamp:      c.Committer.When,
+	}
+}
+
+// FindDefaultBranch get the default branch of the remote, falling back to a local main or master branch
+func FindDefaultBranch(ctx context.Context, file, remoteName string) (string, error) {
+	if remoteName == "" {
+		remoteName = "origin"
+	}
+
+	repo, err := git.PlainOpenWithOptions(
+		file,
+		&git.PlainOpenOptions{
+			DetectDotGit:          true,
+			EnableDotGitCommonDir: true,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	remoteHead, err := repo.Reference(plumbing.NewRemoteHEADReferenceName(remoteName), false)
+	if err == nil && remoteHead.Type() == plumbing.SymbolicReference {
+		return strings.TrimPrefix(remoteHead.Target().String(), fmt.Sprintf("refs/remotes/%s/", remoteName)), nil
+	}
+	for _, branch := range []string{"main", "master"} {
+		if _, err := repo.Reference(plumbing.NewBranchReferenceName(branch), false); err == nil {
+			common.Logger(ctx).Debugf("Using the local branch '%s' as default branch", branch)
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("unable to find the default branch of the remote '%s'", remoteName)
+}
+
+func resolveCommit(repo *git.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve '%s': %w", rev, err)
+	}
+	return repo.CommitObject(*hash)
+}
+
 // FindGithubRepo get the repo
 func FindGithubRepo(ctx context.Context, file, githubInstance, remoteName string) (string, error) {
 	if remoteName == "" {
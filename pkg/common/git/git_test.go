@@ -177,6 +177,108 @@ func TestGitFindRef(t *testing.T) {
 	}
 }
 
+func TestGitFindChangedFiles(t *testing.T) {
+	basedir := testDir(t)
+	gitConfig()
+
+	writeFile := func(t *testing.T, dir, name string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644))
+	}
+	commit := func(t *testing.T, dir string, names ...string) {
+		for _, name := range names {
+			writeFile(t, dir, name)
+		}
+		require.NoError(t, gitCmd("-C", dir, "add", "."))
+		require.NoError(t, gitCmd("-C", dir, "commit", "-m", "msg"))
+	}
+
+	for name, tt := range map[string]struct {
+		Prepare    func(t *testing.T, dir string)
+		Base, Head string
+		Files      []string
+	}{
+		"initial_commit": {
+			Prepare: func(t *testing.T, dir string) {
+				commit(t, dir, "README.md", "src/main.go")
+			},
+			Files: []string{"README.md", "src/main.go"},
+		},
+		"head_commit": {
+			Prepare: func(t *testing.T, dir string) {
+				commit(t, dir, "README.md")
+				commit(t, dir, "docs/index.md")
+			},
+			Files: []string{"docs/index.md"},
+		},
+		"uncommitted_changes": {
+			Prepare: func(t *testing.T, dir string) {
+				commit(t, dir, "README.md")
+				commit(t, dir, "docs/index.md")
+				writeFile(t, dir, "src/main.go")
+			},
+			Files: []string{"docs/index.md", "src/main.go"},
+		},
+		"branch_against_base": {
+			Prepare: func(t *testing.T, dir string) {
+				commit(t, dir, "README.md")
+				require.NoError(t, gitCmd("-C", dir, "checkout", "-b", "feature"))
+				commit(t, dir, "src/a.go")
+				commit(t, dir, "src/b.go")
+				require.NoError(t, gitCmd("-C", dir, "checkout", "master"))
+				commit(t, dir, "docs/index.md")
+			},
+			Base:  "master",
+			Head:  "feature",
+			Files: []string{"src/a.go", "src/b.go"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(basedir, name)
+			require.NoError(t, os.MkdirAll(dir, 0o755))
+			require.NoError(t, gitCmd("-C", dir, "init", "--initial-branch=master"))
+			require.NoError(t, cleanGitHooks(dir))
+			tt.Prepare(t, dir)
+			files, err := FindChangedFiles(context.Background(), dir, tt.Base, tt.Head)
+			require.NoError(t, err)
+			require.Equal(t, tt.Files, files)
+		})
+	}
+}
+
+func TestGitFindCommits(t *testing.T) {
+	dir := testDir(t)
+	gitConfig()
+	require.NoError(t, gitCmd("-C", dir, "init", "--initial-branch=main"))
+	require.NoError(t, cleanGitHooks(dir))
+	require.NoError(t, gitCmd("-C", dir, "commit", "--allow-empty", "-m", "base"))
+	require.NoError(t, gitCmd("-C", dir, "checkout", "-b", "feature"))
+	require.NoError(t, gitCmd("-C", dir, "commit", "--allow-empty", "-m", "first"))
+	require.NoError(t, gitCmd("-C", dir, "commit", "--allow-empty", "-m", "second"))
+	require.NoError(t, gitCmd("-C", dir, "checkout", "main"))
+	require.NoError(t, gitCmd("-C", dir, "commit", "--allow-empty", "-m", "main"))
+
+	messages := func(commits []Commit) []string {
+		rtn := []string{}
+		for _, c := range commits {
+			rtn = append(rtn, c.Message)
+		}
+		return rtn
+	}
+
+	commits, err := FindCommits(context.Background(), dir, "main", "feature")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first\n", "second\n"}, messages(commits))
+
+	commits, err = FindCommits(context.Background(), dir, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main\n"}, messages(commits))
+
+	branch, err := FindDefaultBranch(context.Background(), dir, "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "main", branch)
+}
+
 func TestGitCloneExecutor(t *testing.T) {
 	for name, tt := range map[string]struct {
 		Err      error
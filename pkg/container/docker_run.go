@@ -6,6 +6,8 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -15,6 +17,8 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"dario.cat/mergo"
 	"github.com/Masterminds/semver"
@@ -161,6 +165,15 @@ func (cr *containerReference) Exec(command []string, env map[string]string, user
 	).IfNot(common.Dryrun)
 }
 
+// Shell opens bash, or sh if bash is not installed, in the container with a terminal attached to stdin and stdout
+func (cr *containerReference) Shell(env map[string]string, workdir string, stdin io.Reader, stdout io.Writer) common.Executor {
+	return common.NewPipelineExecutor(
+		cr.connect(),
+		cr.find(),
+		cr.shell(env, workdir, stdin, stdout),
+	).IfNot(common.Dryrun)
+}
+
 func (cr *containerReference) Remove() common.Executor {
 	return common.NewPipelineExecutor(
 		cr.connect(),
@@ -589,6 +602,9 @@ func (cr *containerReference) exec(cmd []string, env map[string]string, user, wo
 		for k, v := range env {
 			envList = append(envList, fmt.Sprintf("%s=%s", k, v))
 		}
+		// the processes of the exec inherit the marker, so they can be signalled if the step is cancelled
+		marker := newExecMarker()
+		envList = append(envList, marker)
 
 		var wd string
 		if workdir != "" {
@@ -623,7 +639,7 @@ func (cr *containerReference) exec(cmd []string, env map[string]string, user, wo
 		}
 		defer resp.Close()
 
-		err = cr.waitForCommand(ctx, isTerminal, resp)
+		err = cr.waitForCommand(ctx, isTerminal, resp, cr.signalExec(ctx, marker, user))
 		if err != nil {
 			return err
 		}
@@ -685,10 +701,66 @@ func (cr *containerReference) tryReadGID() common.Executor {
 	return cr.tryReadID("-g", func(id int) { cr.GID = id })
 }
 
-func (cr *containerReference) waitForCommand(ctx context.Context, isTerminal bool, resp types.HijackedResponse) error {
+func (cr *containerReference) shell(env map[string]string, workdir string, stdin io.Reader, stdout io.Writer) common.Executor {
+	return func(ctx context.Context) error {
+		envList := make([]string, 0, len(env))
+		for k, v := range env {
+			envList = append(envList, fmt.Sprintf("%s=%s", k, v))
+		}
+		wd := cr.input.WorkingDir
+		if strings.HasPrefix(workdir, "/") {
+			wd = workdir
+		} else if workdir != "" {
+			wd = fmt.Sprintf("%s/%s", cr.input.WorkingDir, workdir)
+		}
+
+		idResp, err := cr.cli.ContainerExecCreate(ctx, cr.id, container.ExecOptions{
+			Cmd:          []string{"sh", "-c", "if command -v bash >/dev/null; then exec bash; else exec sh; fi"},
+			WorkingDir:   wd,
+			Env:          envList,
+			Tty:          true,
+			AttachStdin:  true,
+			AttachStderr: true,
+			AttachStdout: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create exec: %w", err)
+		}
+		resp, err := cr.cli.ContainerExecAttach(ctx, idResp.ID, container.ExecStartOptions{Tty: true})
+		if err != nil {
+			return fmt.Errorf("failed to attach to exec: %w", err)
+		}
+		defer resp.Close()
+
+		done := make(chan struct{})
+		copied := make(chan struct{})
+		input := stdin
+		if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			if state, err := term.MakeRaw(int(f.Fd())); err == nil {
+				defer func() { _ = term.Restore(int(f.Fd()), state) }()
+			}
+			// the copy stops once the shell exited, the keys typed afterwards go to the next prompt
+			input = &terminalReader{file: f, done: done}
+		}
+		go func() {
+			defer close(copied)
+			_, _ = io.Copy(resp.Conn, input)
+			_ = resp.CloseWrite()
+		}()
+		_, err = io.Copy(stdout, resp.Reader)
+		close(done)
+		if input != stdin {
+			<-copied
+		}
+		return err
+	}
+}
+
+func (cr *containerReference) waitForCommand(ctx context.Context, isTerminal bool, resp types.HijackedResponse, signal func(os.Signal) error) error {
 	logger := common.Logger(ctx)
 
-	cmdResponse := make(chan error)
+	cmdResponse := make(chan error, 1)
+	exited := make(chan struct{})
 
 	go func() {
 		var outWriter io.Writer
@@ -707,6 +779,7 @@ func (cr *containerReference) waitForCommand(ctx context.Context, isTerminal boo
 		} else {
 			_, err = io.Copy(outWriter, resp.Reader)
 		}
+		close(exited)
 		cmdResponse <- err
 	}()
 
@@ -717,6 +790,7 @@ func (cr *containerReference) waitForCommand(ctx context.Context, isTerminal boo
 		if err != nil {
 			logger.Warnf("Failed to send CTRL+C: %+s", err)
 		}
+		terminate(ctx, signal, exited)
 
 		// we return the context canceled error to prevent other steps
 		// from executing
@@ -730,6 +804,34 @@ func (cr *containerReference) waitForCommand(ctx context.Context, isTerminal boo
 	}
 }
 
+// newExecMarker returns an environment variable which marks the processes of an exec. The steps see the variable
+// ACT_EXEC_ID in their environment, it must stay there for their processes to be found when the step is cancelled.
+func newExecMarker() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "ACT_EXEC_ID=" + hex.EncodeToString(b)
+}
+
+// signalExec sends a signal to the processes of an exec, which are found by the marker in their environment,
+// as the API of docker can not signal the process of an exec. It needs sh, tr, grep and /proc in the container,
+// it fails on images without them, e.g. distroless images, and the processes are then only killed with the container.
+func (cr *containerReference) signalExec(ctx context.Context, marker string, user string) func(os.Signal) error {
+	return func(sig os.Signal) error {
+		name := "INT"
+		switch sig {
+		case syscall.SIGTERM:
+			name = "TERM"
+		case os.Kill:
+			name = "KILL"
+		}
+		script := fmt.Sprintf(`for p in /proc/[0-9]*; do if tr '\0' '\n' < "$p/environ" 2>/dev/null | grep -qx '%s'; then kill -s %s "${p#/proc/}" 2>/dev/null; fi; done`, marker, name)
+		// the context of the step is cancelled already
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+		defer cancel()
+		return cr.exec([]string{"sh", "-c", script}, map[string]string{}, user, "/")(ctx)
+	}
+}
+
 func (cr *containerReference) CopyTarStream(ctx context.Context, destPath string, tarStream io.Reader) error {
 	if common.Dryrun(ctx) {
 		return nil
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -107,6 +108,11 @@ func (m *mockConn) Close() (err error) {
 }
 
 func TestDockerExecAbort(t *testing.T) {
+	defer func(interrupt, terminate time.Duration) {
+		interruptTimeout, terminateTimeout = interrupt, terminate
+	}(interruptTimeout, terminateTimeout)
+	interruptTimeout, terminateTimeout = 50*time.Millisecond, 50*time.Millisecond
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	conn := &mockConn{}
@@ -118,6 +124,14 @@ func TestDockerExecAbort(t *testing.T) {
 		Conn:   conn,
 		Reader: bufio.NewReader(endlessReader{}),
 	}, nil)
+	// the processes of the exec are signalled, as they never exit they are killed in the end
+	signals := []string{}
+	client.On("ContainerExecCreate", mock.Anything, "123", mock.MatchedBy(func(opts container.ExecOptions) bool {
+		return opts.Cmd[0] == "sh"
+	})).Run(func(args mock.Arguments) {
+		script := args.Get(2).(container.ExecOptions).Cmd[2]
+		signals = append(signals, strings.Fields(script[strings.Index(script, "kill -s "):])[2])
+	}).Return(container.ExecCreateResponse{}, errors.New("no shell"))
 
 	cr := &containerReference{
 		id:  "123",
@@ -139,6 +153,7 @@ func TestDockerExecAbort(t *testing.T) {
 
 	err := <-channel
 	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"INT", "TERM", "KILL"}, signals)
 
 	conn.AssertExpectations(t)
 	client.AssertExpectations(t)
@@ -1,6 +1,11 @@
 package container
 
-import "context"
+import (
+	"context"
+	"io"
+
+	"github.com/nektos/act/pkg/common"
+)
 
 type ExecutionsEnvironment interface {
 	Container
@@ -13,3 +18,9 @@ type ExecutionsEnvironment interface {
 	// On windows PATH and Path are the same key
 	IsEnvironmentCaseInsensitive() bool
 }
+
+// InteractiveShell is implemented by the environments a user can open an interactive shell in, e.g. to debug a failed step
+type InteractiveShell interface {
+	// Shell runs the default shell of the environment attached to stdin and stdout until the user exits it
+	Shell(env map[string]string, workdir string, stdin io.Reader, stdout io.Writer) common.Executor
+}
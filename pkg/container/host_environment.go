@@ -307,6 +307,14 @@ func (e *HostEnvironment) exec(ctx context.Context, command []string, cmdline st
 	cmd.Stderr = e.StdOut
 	cmd.Dir = wd
 	cmd.SysProcAttr = getSysProcAttr(cmdline, false)
+	// a cancelled step is stopped gracefully instead of killing it right away
+	exited := make(chan struct{})
+	cmd.Cancel = func() error {
+		go terminate(ctx, func(sig os.Signal) error {
+			return signalProcess(cmd.Process, sig)
+		}, exited)
+		return nil
+	}
 	var ppty *os.File
 	var tty *os.File
 	defer func() {
@@ -334,10 +342,9 @@ func (e *HostEnvironment) exec(ctx context.Context, command []string, cmdline st
 	if ppty != nil {
 		go writeKeepAlive(ppty)
 	}
+	// the output of a failed command is written out as well before returning its error
 	err = cmd.Run()
-	if err != nil {
-		return err
-	}
+	close(exited)
 	if tty != nil {
 		writer.AutoStop = true
 		if _, err := tty.Write([]byte("\x04")); err != nil {
@@ -371,6 +378,38 @@ func (e *HostEnvironment) ExecWithCmdLine(command []string, cmdline string, env
 	}
 }
 
+// Shell opens the shell of the user, or sh, in the workspace of the host environment
+func (e *HostEnvironment) Shell(env map[string]string, workdir string, stdin io.Reader, stdout io.Writer) common.Executor {
+	return func(ctx context.Context) error {
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "sh"
+			if runtime.GOOS == "windows" {
+				shell = "powershell"
+			}
+		}
+		cmd := exec.CommandContext(ctx, shell)
+		cmd.Env = getEnvListFromMap(env)
+		cmd.Dir = e.Path
+		if workdir != "" {
+			if filepath.IsAbs(workdir) {
+				cmd.Dir = workdir
+			} else {
+				cmd.Dir = filepath.Join(e.Path, workdir)
+			}
+		}
+		cmd.Stdin = stdin
+		cmd.Stdout = stdout
+		cmd.Stderr = stdout
+		// the exit code of the shell is the one of the last command of the user
+		var exitErr *exec.ExitError
+		if err := cmd.Run(); err != nil && !errors.As(err, &exitErr) {
+			return err
+		}
+		return nil
+	}
+}
+
 func (e *HostEnvironment) UpdateFromEnv(srcPath string, env *map[string]string) common.Executor {
 	return parseEnvFile(e, srcPath, env)
 }
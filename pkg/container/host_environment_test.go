@@ -2,12 +2,15 @@ package container
 
 import (
 	"archive/tar"
+	"bytes"
 	"context"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -69,3 +72,48 @@ func TestGetContainerArchive(t *testing.T) {
 	_, err = reader.Next()
 	assert.ErrorIs(t, err, io.EOF)
 }
+
+func TestHostEnvironmentExecCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals can not be sent to processes on windows")
+	}
+	dir := t.TempDir()
+	e := &HostEnvironment{
+		Path:   dir,
+		StdOut: io.Discard,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- e.Exec([]string{"sh", "-c", "trap 'echo interrupted > interrupted.txt; exit 3' INT; touch started.txt; while true; do sleep 0.1; done"}, map[string]string{"PATH": os.Getenv("PATH")}, "", "")(ctx)
+	}()
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(filepath.Join(dir, "started.txt"))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorContains(t, err, "this step has been cancelled")
+	case <-time.After(interruptTimeout):
+		t.Fatal("the step did not stop on SIGINT")
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "interrupted.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "interrupted\n", string(content))
+}
+
+func TestHostEnvironmentExecFailureOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available on windows")
+	}
+	out := &bytes.Buffer{}
+	e := &HostEnvironment{
+		Path:   t.TempDir(),
+		StdOut: out,
+	}
+	err := e.Exec([]string{"sh", "-c", "echo the output of a failed step; exit 1"}, map[string]string{"PATH": os.Getenv("PATH")}, "", "")(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, out.String(), "the output of a failed step", "the output is flushed before the error is returned")
+}
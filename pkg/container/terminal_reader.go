@@ -0,0 +1,36 @@
+//go:build !(WITHOUT_DOCKER || !(linux || darwin || windows || netbsd))
+
+package container
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// terminalPollInterval is the interval at which a terminalReader checks whether it was stopped while no input arrives
+const terminalPollInterval = 100 * time.Millisecond
+
+// terminalReader reads a terminal until done is closed. A read of the terminal can not be interrupted, so it only
+// reads once input is available and the input typed after done is closed is left to the next reader of the terminal.
+type terminalReader struct {
+	file *os.File
+	done <-chan struct{}
+}
+
+func (r *terminalReader) Read(p []byte) (int, error) {
+	for {
+		select {
+		case <-r.done:
+			return 0, io.EOF
+		default:
+		}
+		ready, err := waitForInput(r.file, terminalPollInterval)
+		if err != nil {
+			return 0, err
+		}
+		if ready {
+			return r.file.Read(p)
+		}
+	}
+}
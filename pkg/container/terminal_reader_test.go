@@ -0,0 +1,49 @@
+//go:build !(WITHOUT_DOCKER || !(linux || darwin || netbsd))
+
+package container
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalReader(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+
+	done := make(chan struct{})
+	reader := &terminalReader{file: r, done: done}
+	_, err = w.WriteString("ls\n")
+	require.NoError(t, err)
+	buf := make([]byte, 16)
+	n, err := reader.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ls\n", string(buf[:n]))
+
+	read := make(chan error, 1)
+	go func() {
+		_, err := reader.Read(buf)
+		read <- err
+	}()
+	close(done)
+	select {
+	case err := <-read:
+		assert.Equal(t, io.EOF, err)
+	case <-time.After(time.Second):
+		t.Fatal("the read did not stop")
+	}
+
+	// the input typed after the reader stopped is left to the next reader
+	_, err = w.WriteString("y\n")
+	require.NoError(t, err)
+	n, err = r.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "y\n", string(buf[:n]))
+}
@@ -0,0 +1,19 @@
+//go:build windows && !WITHOUT_DOCKER
+
+package container
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// waitForInput reports whether the console has input within the timeout
+func waitForInput(file *os.File, timeout time.Duration) (bool, error) {
+	event, err := windows.WaitForSingleObject(windows.Handle(file.Fd()), uint32(timeout.Milliseconds()))
+	if err != nil {
+		return false, err
+	}
+	return event == windows.WAIT_OBJECT_0, nil
+}
@@ -0,0 +1,58 @@
+package container
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// the processes of a cancelled step get SIGINT, SIGTERM 7.5 seconds later and are killed after another 2.5 seconds, as on GitHub
+var (
+	interruptTimeout = 7500 * time.Millisecond
+	terminateTimeout = 2500 * time.Millisecond
+)
+
+// terminate stops the processes of a cancelled step gracefully, signal sends a signal to them and
+// exited is closed once they exited. A forced cancellation of the run kills them right away.
+func terminate(ctx context.Context, signal func(os.Signal) error, exited <-chan struct{}) {
+	logger := common.Logger(ctx)
+	var force <-chan struct{}
+	if forceCancelCtx := common.ForceCancelContext(ctx); forceCancelCtx != nil {
+		force = forceCancelCtx.Done()
+	}
+
+	warned := false
+stages:
+	for _, stage := range []struct {
+		signal  os.Signal
+		timeout time.Duration
+	}{
+		{os.Interrupt, interruptTimeout},
+		{syscall.SIGTERM, terminateTimeout},
+	} {
+		logger.Debugf("Sending %v to the processes of the step", stage.signal)
+		if err := signal(stage.signal); err != nil {
+			// e.g. the image of the job container lacks the tools to signal the processes of a step
+			if !warned {
+				logger.Warnf("Unable to stop the processes of the step gracefully, they keep running until the job container is stopped: %v", err)
+				warned = true
+			}
+			logger.Debugf("Failed to send %v: %v", stage.signal, err)
+		}
+		select {
+		case <-exited:
+			return
+		case <-force:
+			break stages
+		case <-time.After(stage.timeout):
+		}
+	}
+
+	logger.Debugf("Killing the processes of the step")
+	if err := signal(os.Kill); err != nil {
+		logger.Warnf("Failed to kill the processes of the step: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+func TestTerminate(t *testing.T) {
+	defer func(interrupt, terminate time.Duration) {
+		interruptTimeout, terminateTimeout = interrupt, terminate
+	}(interruptTimeout, terminateTimeout)
+	interruptTimeout, terminateTimeout = 50*time.Millisecond, 50*time.Millisecond
+
+	table := []struct {
+		name    string
+		exitOn  os.Signal
+		force   bool
+		signals []os.Signal
+	}{
+		{name: "interrupted", exitOn: os.Interrupt, signals: []os.Signal{os.Interrupt}},
+		{name: "terminated", exitOn: syscall.SIGTERM, signals: []os.Signal{os.Interrupt, syscall.SIGTERM}},
+		{name: "killed", exitOn: os.Kill, signals: []os.Signal{os.Interrupt, syscall.SIGTERM, os.Kill}},
+		{name: "forced", exitOn: os.Kill, force: true, signals: []os.Signal{os.Interrupt, os.Kill}},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.force {
+				jobCancelCtx, cancel := context.WithCancel(ctx)
+				cancel()
+				var forceCancel context.CancelFunc
+				ctx, forceCancel = common.WithCancelTimeout(common.WithJobCancelContext(ctx, jobCancelCtx), time.Millisecond)
+				defer forceCancel()
+				<-ctx.Done()
+			}
+
+			exited := make(chan struct{})
+			signals := []os.Signal{}
+			terminate(ctx, func(sig os.Signal) error {
+				signals = append(signals, sig)
+				if sig == tt.exitOn {
+					close(exited)
+				}
+				return nil
+			}, exited)
+			assert.Equal(t, tt.signals, signals)
+		})
+	}
+}
+
+func TestTerminateSignalFailure(t *testing.T) {
+	defer func(interrupt, terminate time.Duration) {
+		interruptTimeout, terminateTimeout = interrupt, terminate
+	}(interruptTimeout, terminateTimeout)
+	interruptTimeout, terminateTimeout = 10*time.Millisecond, 10*time.Millisecond
+
+	logger, hook := test.NewNullLogger()
+	ctx := common.WithLogger(context.Background(), logger)
+	exited := make(chan struct{})
+	terminate(ctx, func(sig os.Signal) error {
+		if sig == os.Kill {
+			close(exited)
+			return nil
+		}
+		return errors.New("sh: not found")
+	}, exited)
+
+	// the failure of the graceful stop is reported once
+	warnings := []string{}
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel {
+			warnings = append(warnings, entry.Message)
+		}
+	}
+	assert.Equal(t, []string{"Unable to stop the processes of the step gracefully, they keep running until the job container is stopped: sh: not found"}, warnings)
+}
@@ -24,3 +24,11 @@ func getSysProcAttr(_ string, tty bool) *syscall.SysProcAttr {
 func openPty() (*os.File, *os.File, error) {
 	return pty.Open()
 }
+
+// signalProcess sends a signal to the process group of a process
+func signalProcess(process *os.Process, sig os.Signal) error {
+	if s, ok := sig.(syscall.Signal); ok {
+		return syscall.Kill(-process.Pid, s)
+	}
+	return process.Signal(sig)
+}
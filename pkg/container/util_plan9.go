@@ -15,3 +15,8 @@ func getSysProcAttr(cmdLine string, tty bool) *syscall.SysProcAttr {
 func openPty() (*os.File, *os.File, error) {
 	return nil, nil, errors.New("Unsupported")
 }
+
+// signalProcess kills a process, signals can not be sent to processes on this platform
+func signalProcess(process *os.Process, _ os.Signal) error {
+	return process.Kill()
+}
@@ -0,0 +1,268 @@
+// Package dashboard shows the jobs of a run of act in a full-screen terminal dashboard
+package dashboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+// maxLogLines is the number of lines of the log of a job kept by the dashboard
+const maxLogLines = 10000
+
+const (
+	statusPending   = "pending"
+	statusRunning   = "running"
+	statusSuccess   = "success"
+	statusFailure   = "failure"
+	statusSkipped   = "skipped"
+	statusCancelled = "cancelled"
+)
+
+// Dashboard keeps the status and the log of the jobs of a plan, its job loggers are fed by the runner
+type Dashboard struct {
+	mu        sync.Mutex
+	stages    [][]*plannedJob
+	global    *logBuffer
+	startedAt time.Time
+	now       func() time.Time
+	changed   chan struct{}
+
+	// state of the terminal view
+	selected int
+	scroll   int
+}
+
+// plannedJob is a job of the plan, a job with a matrix has one run per combination
+type plannedJob struct {
+	workflow string
+	jobID    string
+	name     string
+	runs     []*jobRun
+}
+
+// jobRun is a job as it is run by the runner
+type jobRun struct {
+	name       string
+	matrix     map[string]interface{}
+	status     string
+	step       string
+	startedAt  time.Time
+	finishedAt time.Time
+	log        *logBuffer
+}
+
+// New returns the dashboard of the jobs of the plan
+func New(plan *model.Plan) *Dashboard {
+	d := &Dashboard{
+		global:  &logBuffer{},
+		now:     time.Now,
+		changed: make(chan struct{}, 1),
+	}
+	d.startedAt = d.now()
+	for _, stage := range plan.Stages {
+		jobs := []*plannedJob{}
+		for _, run := range stage.Runs {
+			name := run.JobID
+			if job := run.Job(); job != nil && job.Name != "" {
+				name = job.Name
+			}
+			jobs = append(jobs, &plannedJob{workflow: run.Workflow.Name, jobID: run.JobID, name: name})
+		}
+		d.stages = append(d.stages, jobs)
+	}
+	return d
+}
+
+// WithJobLogger returns the logger of a job, the dashboard is a runner.JobLoggerFactory
+func (d *Dashboard) WithJobLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(jobLogWriter{d})
+	logger.SetLevel(logrus.GetLevel())
+	// the runner masks the secrets before the entries are formatted
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	return logger
+}
+
+// GlobalLogWriter receives the log of act which does not belong to a job
+func (d *Dashboard) GlobalLogWriter() io.Writer {
+	return d.global
+}
+
+// jobLogWriter receives the entries of the job loggers as JSON, logrus writes one or more complete entries at once
+type jobLogWriter struct {
+	d *Dashboard
+}
+
+func (w jobLogWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(bytes.TrimSuffix(p, []byte("\n")), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		entry := map[string]interface{}{}
+		if json.Unmarshal(line, &entry) != nil {
+			_, _ = w.d.global.Write(append(line, '\n'))
+			continue
+		}
+		w.d.handleEntry(entry)
+	}
+	return len(p), nil
+}
+
+func (d *Dashboard) handleEntry(entry map[string]interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	defer d.notify()
+
+	job, _ := entry["job"].(string)
+	jobID, _ := entry["jobID"].(string)
+	matrix, _ := entry["matrix"].(map[string]interface{})
+	run := d.jobRun(job, jobID, matrix)
+
+	if run.status == statusPending {
+		run.status = statusRunning
+		run.startedAt = d.now()
+	}
+	if step, ok := entry["step"].(string); ok && entry["stage"] == "Main" {
+		run.step, _, _ = strings.Cut(step, "\n")
+	}
+	if result, ok := entry["jobResult"].(string); ok {
+		run.status = result
+		run.step = ""
+		run.finishedAt = d.now()
+	}
+
+	message, _ := entry["msg"].(string)
+	prefix := ""
+	if entry["raw_output"] == true {
+		prefix = "| "
+	} else if entry["level"] == "debug" {
+		prefix = "[DEBUG] "
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(message, "\n"), "\n") {
+		run.log.add(prefix + line)
+	}
+}
+
+// jobRun returns the run of a job, the job field of the runner is <workflow name>/<job name>
+func (d *Dashboard) jobRun(job string, jobID string, matrix map[string]interface{}) *jobRun {
+	var planned *plannedJob
+	for _, stage := range d.stages {
+		for _, p := range stage {
+			if p.jobID == jobID && strings.HasPrefix(job, p.workflow+"/") {
+				planned = p
+			}
+		}
+	}
+	if planned == nil {
+		// e.g. the jobs of reusable workflows and of chained workflow runs
+		planned = &plannedJob{jobID: jobID, name: job}
+		d.stages = append(d.stages, []*plannedJob{planned})
+	}
+	for _, run := range planned.runs {
+		if run.name == job {
+			return run
+		}
+	}
+	if len(matrix) == 0 {
+		matrix = nil
+	}
+	run := &jobRun{name: job, matrix: matrix, status: statusPending, log: &logBuffer{}}
+	planned.runs = append(planned.runs, run)
+	return run
+}
+
+func (d *Dashboard) notify() {
+	select {
+	case d.changed <- struct{}{}:
+	default:
+	}
+}
+
+// status of a planned job, the results of the runs of a matrix are merged
+func (p *plannedJob) status() string {
+	if len(p.runs) == 0 {
+		return statusPending
+	}
+	status := ""
+	for _, run := range p.runs {
+		switch {
+		case run.status == statusRunning || run.status == statusPending:
+			return statusRunning
+		case run.status == statusFailure:
+			status = statusFailure
+		case run.status == statusCancelled && status != statusFailure:
+			status = statusCancelled
+		case run.status == statusSuccess && status != statusFailure && status != statusCancelled:
+			status = statusSuccess
+		case status == "":
+			status = run.status
+		}
+	}
+	return status
+}
+
+func (r *jobRun) elapsed(now time.Time) time.Duration {
+	if r.startedAt.IsZero() {
+		return 0
+	}
+	if !r.finishedAt.IsZero() {
+		now = r.finishedAt
+	}
+	return now.Sub(r.startedAt)
+}
+
+// logBuffer keeps the last lines of a log
+type logBuffer struct {
+	mu      sync.Mutex
+	lines   []string
+	partial string
+}
+
+func (b *logBuffer) add(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lines = append(b.lines, strings.Split(strings.TrimSuffix(line, "\n"), "\n")...)
+	if len(b.lines) > maxLogLines {
+		b.lines = b.lines[len(b.lines)-maxLogLines:]
+	}
+}
+
+// Write adds the complete lines of p to the log
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	text := b.partial + string(p)
+	i := strings.LastIndex(text, "\n")
+	b.partial = text[i+1:]
+	b.mu.Unlock()
+	if i >= 0 {
+		b.add(text[:i])
+	}
+	return len(p), nil
+}
+
+func (b *logBuffer) tail(n int, skip int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	end := max(len(b.lines)-skip, 0)
+	start := max(end-n, 0)
+	return append([]string{}, b.lines[start:end]...)
+}
+
+func (b *logBuffer) len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.lines)
+}
+
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
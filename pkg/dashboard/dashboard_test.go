@@ -0,0 +1,134 @@
+package dashboard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDashboard() (*Dashboard, *time.Time) {
+	workflow := &model.Workflow{Name: "CI", Jobs: map[string]*model.Job{
+		"build":  {Name: "Build"},
+		"test":   {},
+		"deploy": {},
+	}}
+	d := New(&model.Plan{Stages: []*model.Stage{
+		{Runs: []*model.Run{{Workflow: workflow, JobID: "build"}, {Workflow: workflow, JobID: "test"}}},
+		{Runs: []*model.Run{{Workflow: workflow, JobID: "deploy"}}},
+	}})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.startedAt = now
+	d.now = func() time.Time { return now }
+	return d, &now
+}
+
+func jobLogger(d *Dashboard, job string, jobID string, matrix map[string]interface{}) *logrus.Entry {
+	logger := d.WithJobLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	return logger.WithFields(logrus.Fields{"job": job, "jobID": jobID, "matrix": matrix})
+}
+
+func TestDashboard(t *testing.T) {
+	d, now := newTestDashboard()
+	build := jobLogger(d, "CI/Build", "build", map[string]interface{}{})
+	test1 := jobLogger(d, "CI/test-1", "test", map[string]interface{}{"node": 18})
+	test2 := jobLogger(d, "CI/test-2", "test", map[string]interface{}{"node": 20})
+
+	build.WithFields(logrus.Fields{"step": "Checkout", "stage": "Main"}).Info("⭐ Run Main Checkout")
+	test1.WithFields(logrus.Fields{"step": "Run tests", "stage": "Main"}).Info("⭐ Run Main Run tests")
+	test1.WithField("raw_output", true).Info("FAIL: TestSomething")
+	test1.WithField("jobResult", "failure").Info("🏁  Job failed")
+	*now = now.Add(65 * time.Second)
+	build.WithField("raw_output", true).Info("building\ndone")
+	build.Debug("evaluating")
+	test2.WithField("jobResult", "success").Info("🏁  Job succeeded")
+
+	lines := d.render(70, 20, false)
+	assert.Equal(t, []string{
+		"act  ● 1 running  ○ 1 pending  ✓ 1 success  ✗ 1 failure          01:05",
+		"  Stage 1",
+		">   ● Build  01:05  Checkout",
+		"    ✗ test",
+		"      ✗ node=18  00:00",
+		"      ✓ node=20  00:00",
+		"  Stage 2",
+		"    ○ deploy",
+		"── Build ─────────────────────────────────────────────────────────────",
+		"⭐ Run Main Checkout",
+		"| building",
+		"| done",
+		"[DEBUG] evaluating",
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+		"↑/↓ select job  PgUp/PgDn scroll log  q/Ctrl-C cancel",
+	}, lines)
+
+	d.move(1)
+	d.move(1)
+	d.move(1)
+	lines = d.render(70, 20, false)
+	assert.Equal(t, ">     ✓ node=20  00:00", lines[5])
+	d.move(-1)
+	lines = d.render(70, 20, false)
+	assert.Equal(t, ">     ✗ node=18  00:00", lines[4])
+	assert.Equal(t, "| FAIL: TestSomething", lines[10])
+
+	build.WithField("jobResult", "success").Info("🏁  Job succeeded")
+	out := &bytes.Buffer{}
+	d.WriteSummary(out)
+	assert.Equal(t, strings.Join([]string{
+		"Stage 1",
+		"  ✓ Build  01:05",
+		"  ✗ test",
+		"    ✗ node=18  00:00",
+		"    ✓ node=20  00:00",
+		"Stage 2",
+		"  ○ deploy",
+		"",
+		"End of the log of CI/test-1:",
+		"  ⭐ Run Main Run tests",
+		"  | FAIL: TestSomething",
+		"  🏁  Job failed",
+		"",
+	}, "\n"), out.String())
+}
+
+func TestDashboardGlobalLog(t *testing.T) {
+	d, _ := newTestDashboard()
+	_, _ = d.GlobalLogWriter().Write([]byte("level=info msg=\"Using docker host\"\nlevel=warn"))
+	lines := d.render(40, 8, false)
+	assert.Equal(t, "> act", lines[1])
+	assert.Equal(t, "level=info msg=\"Using docker host\"", lines[5])
+
+	// the log is scrolled back by lines, never past its beginning
+	_, _ = d.GlobalLogWriter().Write([]byte(" msg=\"Done\"\n"))
+	d.scrollLog(10)
+	lines = d.render(40, 8, false)
+	assert.Equal(t, "level=info msg=\"Using docker host\"", lines[5])
+	assert.Equal(t, "level=warn msg=\"Done\"", lines[6])
+}
+
+func TestTruncate(t *testing.T) {
+	table := []struct {
+		in    string
+		width int
+		out   string
+	}{
+		{"hello", 10, "hello"},
+		{"hello world", 5, "hello"},
+		{"✓ done", 3, "✓ d"},
+		{"\x1b[32m✓\x1b[0m done", 3, "\x1b[32m✓\x1b[0m d"},
+	}
+	for _, tt := range table {
+		assert.Equal(t, tt.out, truncate(tt.in, tt.width), tt.in)
+	}
+}
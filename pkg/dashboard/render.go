@@ -0,0 +1,276 @@
+package dashboard
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	colorReset   = "\x1b[0m"
+	colorInverse = "\x1b[7m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorGray    = "\x1b[90m"
+)
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// item is a line of the tree of the jobs, jobs and their runs can be selected to show their log
+type item struct {
+	depth  int
+	text   string
+	status string
+	log    *logBuffer
+	title  string // title of the log
+}
+
+// line returns the text of the item indented by its depth, with the icon of its status
+func (it item) line(color bool) string {
+	text := it.text
+	if it.status != "" {
+		text = statusIcon(it.status, color) + " " + text
+	} else if it.log == nil {
+		text = bold(text, color)
+	}
+	return strings.Repeat("  ", it.depth) + text
+}
+
+// items returns the lines of the tree of the stages, jobs and runs of matrices
+func (d *Dashboard) items(now time.Time) []item {
+	items := []item{}
+	if d.global.len() > 0 {
+		items = append(items, item{text: "act", log: d.global, title: "act"})
+	}
+	for i, stage := range d.stages {
+		items = append(items, item{text: fmt.Sprintf("Stage %d", i+1)})
+		for _, job := range stage {
+			if len(job.runs) == 1 && job.runs[0].matrix == nil {
+				run := job.runs[0]
+				text := fmt.Sprintf("%s  %s%s", job.name, formatElapsed(run.elapsed(now)), currentStep(run))
+				items = append(items, item{depth: 1, text: text, status: run.status, log: run.log, title: job.name})
+				continue
+			}
+			items = append(items, item{depth: 1, text: job.name, status: job.status()})
+			for _, run := range job.runs {
+				items = append(items, item{
+					depth:  2,
+					text:   fmt.Sprintf("%s  %s%s", runName(run), formatElapsed(run.elapsed(now)), currentStep(run)),
+					status: run.status,
+					log:    run.log,
+					title:  run.name,
+				})
+			}
+		}
+	}
+	return items
+}
+
+func currentStep(run *jobRun) string {
+	if run.step == "" || run.status != statusRunning {
+		return ""
+	}
+	return "  " + run.step
+}
+
+// runName is the matrix of a run, or its name if it has no matrix
+func runName(run *jobRun) string {
+	if len(run.matrix) == 0 {
+		return run.name
+	}
+	keys := make([]string, 0, len(run.matrix))
+	for key := range run.matrix {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, fmt.Sprintf("%s=%v", key, run.matrix[key]))
+	}
+	return strings.Join(values, ", ")
+}
+
+func statusIcon(status string, color bool) string {
+	icon, code := "?", ""
+	switch status {
+	case statusPending:
+		icon, code = "○", colorGray
+	case statusRunning:
+		icon, code = "●", colorBlue
+	case statusSuccess:
+		icon, code = "✓", colorGreen
+	case statusFailure:
+		icon, code = "✗", colorRed
+	case statusSkipped:
+		icon, code = "-", colorGray
+	case statusCancelled:
+		icon, code = "⊘", colorYellow
+	}
+	if !color {
+		return icon
+	}
+	return code + icon + colorReset
+}
+
+// render returns the lines of the dashboard for a terminal of the size
+func (d *Dashboard) render(width int, height int, color bool) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	items := d.items(now)
+	d.selected = selectable(items, d.selected, 0)
+
+	lines := []string{d.titleBar(width, color, now)}
+
+	// the tree takes up to half of the screen, scrolled to the selected item
+	treeHeight := min(len(items), max(height/2-1, 1))
+	offset := max(d.selected-treeHeight+1, 0)
+	for i := offset; i < offset+treeHeight && i < len(items); i++ {
+		marker := "  "
+		if i == d.selected {
+			marker = "> "
+		}
+		lines = append(lines, truncate(marker+items[i].line(color), width))
+	}
+
+	var selected item
+	if d.selected < len(items) {
+		selected = items[d.selected]
+	}
+	logHeight := max(height-len(lines)-2, 0)
+	lines = append(lines, truncate(fmt.Sprintf("── %s %s", selected.title, strings.Repeat("─", width)), width))
+	if selected.log != nil {
+		d.scroll = min(d.scroll, max(selected.log.len()-logHeight, 0))
+		for _, line := range selected.log.tail(logHeight, d.scroll) {
+			lines = append(lines, truncate(ansiEscape.ReplaceAllString(line, ""), width))
+		}
+	}
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	help := "↑/↓ select job  PgUp/PgDn scroll log  q/Ctrl-C cancel"
+	if color {
+		help = colorGray + help + colorReset
+	}
+	return append(lines, truncate(help, width))
+}
+
+func (d *Dashboard) titleBar(width int, color bool, now time.Time) string {
+	counts := map[string]int{}
+	for _, stage := range d.stages {
+		for _, job := range stage {
+			if len(job.runs) == 0 {
+				counts[statusPending]++
+			}
+			for _, run := range job.runs {
+				counts[run.status]++
+			}
+		}
+	}
+	parts := []string{"act"}
+	for _, status := range []string{statusRunning, statusPending, statusSuccess, statusFailure, statusCancelled, statusSkipped} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d %s", statusIcon(status, false), counts[status], status))
+		}
+	}
+	title := strings.Join(parts, "  ")
+	elapsed := formatElapsed(now.Sub(d.startedAt))
+	title += strings.Repeat(" ", max(width-len([]rune(title))-len(elapsed), 1)) + elapsed
+	title = truncate(title, width)
+	if color {
+		return colorInverse + title + colorReset
+	}
+	return title
+}
+
+// selectable returns the next item with a log in the direction, or the item itself with the direction 0
+func selectable(items []item, index int, direction int) int {
+	if len(items) == 0 {
+		return 0
+	}
+	index = max(min(index, len(items)-1), 0)
+	if direction == 0 {
+		if items[index].log != nil {
+			return index
+		}
+		direction = 1
+	}
+	for i := index + direction; i >= 0 && i < len(items); i += direction {
+		if items[i].log != nil {
+			return i
+		}
+	}
+	return index
+}
+
+// move moves the selection by one selectable item
+func (d *Dashboard) move(direction int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.selected = selectable(d.items(d.now()), d.selected, direction)
+	d.scroll = 0
+}
+
+func (d *Dashboard) scrollLog(lines int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.scroll = max(d.scroll+lines, 0)
+}
+
+// WriteSummary writes the final status of the jobs and the end of the log of the failed jobs
+func (d *Dashboard) WriteSummary(w io.Writer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	for _, it := range d.items(now) {
+		if it.log == d.global {
+			continue
+		}
+		fmt.Fprintln(w, it.line(false))
+	}
+	for _, it := range d.items(now) {
+		if it.status != statusFailure || it.log == nil {
+			continue
+		}
+		fmt.Fprintf(w, "\nEnd of the log of %s:\n", it.title)
+		for _, line := range it.log.tail(30, 0) {
+			fmt.Fprintf(w, "  %s\n", ansiEscape.ReplaceAllString(line, ""))
+		}
+	}
+}
+
+func bold(s string, color bool) string {
+	if !color {
+		return s
+	}
+	return "\x1b[1m" + s + colorReset
+}
+
+// truncate cuts a line to the width of the terminal, ANSI escape sequences do not count
+func truncate(s string, width int) string {
+	visible := 0
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			if loc := ansiEscape.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+				b.WriteString(s[i : i+loc[1]])
+				i += loc[1]
+				continue
+			}
+		}
+		if visible >= width {
+			break
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteRune(r)
+		visible++
+		i += size
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package dashboard
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// refreshInterval is the interval at which the elapsed times are updated when no log arrives
+const refreshInterval = 250 * time.Millisecond
+
+// Start shows the dashboard full-screen on the terminal until stop is called, Ctrl-C calls cancel
+func (d *Dashboard) Start(out *os.File, in *os.File, cancel func()) (stop func(), err error) {
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	// alternate screen, hidden cursor
+	_, _ = out.WriteString("\x1b[?1049h\x1b[?25l")
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.draw(out)
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			case <-d.changed:
+			}
+			d.draw(out)
+		}
+	}()
+	// the input is read until the process exits, a pending read cannot be interrupted
+	go d.readInput(in, done, cancel)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+			_, _ = out.WriteString("\x1b[?25h\x1b[?1049l")
+			_ = term.Restore(int(in.Fd()), state)
+		})
+	}, nil
+}
+
+func (d *Dashboard) draw(out *os.File) {
+	width, height, err := term.GetSize(int(out.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+	var b bytes.Buffer
+	b.WriteString("\x1b[H")
+	b.WriteString(strings.Join(d.render(width, height, true), "\x1b[K\r\n"))
+	b.WriteString("\x1b[K\x1b[J")
+	_, _ = out.Write(b.Bytes())
+}
+
+func (d *Dashboard) readInput(in *os.File, done chan struct{}, cancel func()) {
+	buf := make([]byte, 16)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+		select {
+		case <-done:
+			return
+		default:
+		}
+		d.handleKey(string(buf[:n]), cancel)
+		d.notify()
+	}
+}
+
+func (d *Dashboard) handleKey(key string, cancel func()) {
+	switch key {
+	case "\x1b[A", "k":
+		d.move(-1)
+	case "\x1b[B", "j":
+		d.move(1)
+	case "\x1b[5~":
+		d.scrollLog(10)
+	case "\x1b[6~":
+		d.scrollLog(-10)
+	case "\x03", "q":
+		cancel()
+	}
+}
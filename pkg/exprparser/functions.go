@@ -261,7 +261,8 @@ func (impl *interperterImpl) jobSuccess() (bool, error) {
 	jobNeeds := impl.getNeedsTransitive(impl.config.Run.Job())
 
 	for _, needs := range jobNeeds {
-		if jobs[needs].Result != "success" {
+		// a failure allowed by continue-on-error doesn't prevent the dependent jobs from running
+		if jobs[needs].Result != "success" && !jobs[needs].ContinuedOnError {
 			return false, nil
 		}
 	}
@@ -0,0 +1,252 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/common/git"
+)
+
+// EventPayloadOptions describes the repository an event payload is generated from
+type EventPayloadOptions struct {
+	RepoPath       string            // path of the local git repository
+	GithubInstance string            // GitHub instance of the repository, e.g. github.com
+	RemoteName     string            // git remote the repository name and default branch are read from
+	DefaultBranch  string            // default branch, read from the remote if empty
+	Actor          string            // user that triggered the event
+	Inputs         map[string]string // inputs of workflow_dispatch events
+}
+
+// ErrUnsupportedEvent is returned for events a payload can not be generated for
+var ErrUnsupportedEvent = errors.New("unsupported event")
+
+// GeneratedEvents lists the events a payload can be generated for
+var GeneratedEvents = []string{"push", "create", "pull_request", "pull_request_target", "release", "workflow_dispatch"}
+
+const zeroSha = "0000000000000000000000000000000000000000"
+
+var (
+	findCommits       = git.FindCommits
+	findDefaultBranch = git.FindDefaultBranch
+	findGithubRepo    = git.FindGithubRepo
+)
+
+// GenerateEventPayload synthesizes the webhook payload of an event from the state of the local repository:
+// the checked out branch or tag, its head commit and the commits it is ahead of the default branch
+func GenerateEventPayload(ctx context.Context, eventName string, opts EventPayloadOptions) (map[string]interface{}, error) {
+	logger := common.Logger(ctx)
+	if !slices.Contains(GeneratedEvents, eventName) {
+		return nil, fmt.Errorf("%w '%s', supported events are %s", ErrUnsupportedEvent, eventName, strings.Join(GeneratedEvents, ", "))
+	}
+
+	_, headSha, err := findGitRevision(ctx, opts.RepoPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the git revision: %w", err)
+	}
+	ref, err := findGitRef(ctx, opts.RepoPath)
+	if err != nil {
+		logger.Debugf("unable to get the git ref: %v", err)
+	}
+	defaultBranch := opts.DefaultBranch
+	if defaultBranch == "" {
+		if defaultBranch, err = findDefaultBranch(ctx, opts.RepoPath, opts.RemoteName); err != nil {
+			logger.Debugf("unable to get the default branch: %v", err)
+			defaultBranch = "master"
+		}
+	}
+	if ref == "" {
+		ref = "refs/heads/" + defaultBranch
+	}
+	githubInstance := opts.GithubInstance
+	if githubInstance == "" {
+		githubInstance = "github.com"
+	}
+	repoName, err := findGithubRepo(ctx, opts.RepoPath, githubInstance, opts.RemoteName)
+	if err != nil || !strings.Contains(repoName, "/") {
+		logger.Debugf("unable to get the repository name: %v", err)
+		repoName = "nektos/act"
+	}
+	owner, name, _ := strings.Cut(repoName, "/")
+	repoURL := fmt.Sprintf("https://%s/%s", githubInstance, repoName)
+	actor := opts.Actor
+	if actor == "" {
+		actor = owner
+	}
+
+	repository := map[string]interface{}{
+		"name":           name,
+		"full_name":      repoName,
+		"owner":          map[string]interface{}{"login": owner, "name": owner},
+		"default_branch": defaultBranch,
+		"html_url":       repoURL,
+		"clone_url":      repoURL + ".git",
+	}
+	sender := map[string]interface{}{"login": actor}
+	event := map[string]interface{}{
+		"repository": repository,
+		"sender":     sender,
+	}
+
+	headCommits, err := findCommits(ctx, opts.RepoPath, "", headSha)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the head commit: %w", err)
+	}
+	headCommit := headCommits[0]
+	branch, isBranch := strings.CutPrefix(ref, "refs/heads/")
+	tag, isTag := strings.CutPrefix(ref, "refs/tags/")
+
+	switch eventName {
+	case "push":
+		before := zeroSha
+		commits := []git.Commit{headCommit}
+		if isBranch && branch != defaultBranch {
+			// a branch is pushed with the commits it is ahead of the default branch
+			if ahead, err := findCommits(ctx, opts.RepoPath, defaultBranch, headSha); err == nil && len(ahead) > 0 {
+				commits = ahead
+				before = parentSha(ctx, opts.RepoPath, ahead[0].SHA)
+			}
+		} else if !isTag {
+			before = parentSha(ctx, opts.RepoPath, headSha)
+		}
+		event["ref"] = ref
+		event["before"] = before
+		event["after"] = headSha
+		event["created"] = before == zeroSha
+		event["deleted"] = false
+		event["forced"] = false
+		event["base_ref"] = nil
+		event["compare"] = fmt.Sprintf("%s/compare/%s...%s", repoURL, before[:12], headSha[:12])
+		event["commits"] = commitPayloads(commits, repoURL)
+		event["head_commit"] = commitPayload(headCommit, repoURL)
+		event["pusher"] = map[string]interface{}{"name": headCommit.AuthorName, "email": headCommit.AuthorEmail}
+	case "create":
+		if isTag {
+			event["ref"] = tag
+			event["ref_type"] = "tag"
+		} else {
+			event["ref"] = branch
+			event["ref_type"] = "branch"
+		}
+		event["master_branch"] = defaultBranch
+		event["pusher_type"] = "user"
+	case "pull_request", "pull_request_target":
+		baseSha := headSha
+		commits := []git.Commit{}
+		if ahead, err := findCommits(ctx, opts.RepoPath, defaultBranch, headSha); err == nil {
+			commits = ahead
+		}
+		if sha, err := resolveSha(ctx, opts.RepoPath, defaultBranch); err == nil {
+			baseSha = sha
+		}
+		changedFiles, _ := findChangedFiles(ctx, opts.RepoPath, defaultBranch, headSha)
+		if !isBranch {
+			branch = headSha[:7]
+		}
+		repo := map[string]interface{}{"name": name, "full_name": repoName, "html_url": repoURL}
+		event["action"] = "opened"
+		event["number"] = 1
+		event["pull_request"] = map[string]interface{}{
+			"number":        1,
+			"state":         "open",
+			"title":         commitTitle(headCommit.Message),
+			"body":          "",
+			"html_url":      fmt.Sprintf("%s/pull/1", repoURL),
+			"user":          sender,
+			"draft":         false,
+			"merged":        false,
+			"commits":       len(commits),
+			"changed_files": len(changedFiles),
+			"head": map[string]interface{}{
+				"ref":   branch,
+				"sha":   headSha,
+				"label": fmt.Sprintf("%s:%s", owner, branch),
+				"repo":  repo,
+			},
+			"base": map[string]interface{}{
+				"ref":   defaultBranch,
+				"sha":   baseSha,
+				"label": fmt.Sprintf("%s:%s", owner, defaultBranch),
+				"repo":  repo,
+			},
+		}
+	case "release":
+		if !isTag {
+			// the checked out revision has no tag, use its short sha as the name of the release
+			tag = headSha[:7]
+		}
+		target := defaultBranch
+		if isBranch {
+			target = branch
+		}
+		event["action"] = "published"
+		event["release"] = map[string]interface{}{
+			"id":               1,
+			"tag_name":         tag,
+			"name":             tag,
+			"target_commitish": target,
+			"draft":            false,
+			"prerelease":       false,
+			"html_url":         fmt.Sprintf("%s/releases/tag/%s", repoURL, tag),
+			"author":           sender,
+			"published_at":     headCommit.Timestamp.UTC().Format(time.RFC3339),
+		}
+	case "workflow_dispatch":
+		inputs := map[string]interface{}{}
+		for k, v := range opts.Inputs {
+			inputs[k] = v
+		}
+		event["ref"] = ref
+		event["inputs"] = inputs
+	}
+	return event, nil
+}
+
+// resolveSha returns the sha of the commit a revision points to
+func resolveSha(ctx context.Context, repoPath string, rev string) (string, error) {
+	commits, err := findCommits(ctx, repoPath, "", rev)
+	if err != nil {
+		return "", err
+	}
+	return commits[0].SHA, nil
+}
+
+func parentSha(ctx context.Context, repoPath string, sha string) string {
+	if parent, err := resolveSha(ctx, repoPath, sha+"^"); err == nil {
+		return parent
+	}
+	return zeroSha
+}
+
+func commitTitle(message string) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return title
+}
+
+func commitPayload(commit git.Commit, repoURL string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        commit.SHA,
+		"message":   strings.TrimSpace(commit.Message),
+		"timestamp": commit.Timestamp.Format(time.RFC3339),
+		"url":       fmt.Sprintf("%s/commit/%s", repoURL, commit.SHA),
+		"distinct":  true,
+		"author":    map[string]interface{}{"name": commit.AuthorName, "email": commit.AuthorEmail},
+		"committer": map[string]interface{}{"name": commit.CommitterName, "email": commit.CommitterEmail},
+	}
+}
+
+func commitPayloads(commits []git.Commit, repoURL string) []interface{} {
+	// like on GitHub, push payloads list at most the 20 most recent commits
+	if len(commits) > 20 {
+		commits = commits[len(commits)-20:]
+	}
+	payloads := make([]interface{}, 0, len(commits))
+	for _, commit := range commits {
+		payloads = append(payloads, commitPayload(commit, repoURL))
+	}
+	return payloads
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/common/git"
+	"github.com/nektos/act/pkg/workflowpattern"
+	log "github.com/sirupsen/logrus"
+)
+
+// EventTrigger describes the event a plan is created for.
+// The activity type, the ref and the changed paths are matched against the types, branch, tag and path filters of the workflows.
+type EventTrigger struct {
+	Name   string   // name of the event, e.g. push
+	Action string   // activity type of the event, e.g. labeled, empty if unknown
+	Ref    string   // ref the filters are matched against, e.g. refs/heads/main (the base branch for pull requests)
+	Paths  []string // files changed by the event, nil if unknown
+
+	Workflow string // name of the completed workflow of a workflow_run event, empty if unknown
+}
+
+// defaultActivityTypes lists the activity types that trigger a workflow when `types` is omitted.
+// Events not listed here are triggered by all of their activity types.
+// https://docs.github.com/en/actions/using-workflows/events-that-trigger-workflows
+var defaultActivityTypes = map[string][]string{
+	"pull_request":        {"opened", "synchronize", "reopened"},
+	"pull_request_target": {"opened", "synchronize", "reopened"},
+}
+
+var findChangedFiles = git.FindChangedFiles
+
+// NewEventTrigger derives the ref and the changed files of an event from its payload, falling back to the local git repository
+func NewEventTrigger(ctx context.Context, eventName string, event map[string]interface{}, repoPath string, defaultBranch string) *EventTrigger {
+	logger := common.Logger(ctx)
+	trigger := &EventTrigger{
+		Name:   eventName,
+		Action: asString(event["action"]),
+	}
+
+	var err error
+	switch eventName {
+	case "push":
+		trigger.Ref = asString(event["ref"])
+		if trigger.Ref == "" {
+			if trigger.Ref, err = findGitRef(ctx, repoPath); err != nil {
+				logger.Debugf("unable to get git ref: %v", err)
+			}
+		}
+		after := asString(event["after"])
+		if !isZeroSha(after) {
+			before := asString(event["before"])
+			if isZeroSha(before) {
+				before = ""
+			}
+			if trigger.Paths, err = findChangedFiles(ctx, repoPath, before, after); err != nil {
+				logger.Debugf("unable to get changed files: %v", err)
+			}
+		}
+	case "pull_request", "pull_request_target":
+		base := asString(nestedMapLookup(event, "pull_request", "base", "ref"))
+		if base == "" {
+			base = defaultBranch
+		}
+		if base == "" {
+			base = asString(nestedMapLookup(event, "repository", "default_branch"))
+		}
+		if base != "" {
+			trigger.Ref = fmt.Sprintf("refs/heads/%s", base)
+		}
+		baseRev := asString(nestedMapLookup(event, "pull_request", "base", "sha"))
+		if baseRev == "" {
+			baseRev = base
+		}
+		if baseRev != "" {
+			head := asString(nestedMapLookup(event, "pull_request", "head", "sha"))
+			if trigger.Paths, err = findChangedFiles(ctx, repoPath, baseRev, head); err != nil {
+				logger.Debugf("unable to get changed files: %v", err)
+			}
+		}
+	case "workflow_run":
+		if branch := asString(nestedMapLookup(event, "workflow_run", "head_branch")); branch != "" {
+			trigger.Ref = fmt.Sprintf("refs/heads/%s", branch)
+		}
+		trigger.Workflow = asString(nestedMapLookup(event, "workflow_run", "name"))
+	}
+
+	return trigger
+}
+
+func isZeroSha(sha string) bool {
+	return sha != "" && strings.Trim(sha, "0") == ""
+}
+
+// skip reports whether the filters of the workflow exclude this trigger
+func (t *EventTrigger) skip(w *Workflow) (bool, error) {
+	filters := w.EventFilters(t.Name)
+	if filters == nil {
+		filters = &EventFilters{}
+	}
+	tw := &plannerTraceWriter{workflow: w}
+
+	if t.Action != "" {
+		types := filters.Types
+		if len(types) == 0 {
+			types = defaultActivityTypes[t.Name]
+		}
+		if len(types) > 0 && !slices.Contains(types, t.Action) {
+			tw.Info("activity type %s does not match %v", t.Action, types)
+			return true, nil
+		}
+	}
+
+	// GitHub only sends workflow_run events of completed workflows to the workflows which list them, a chained workflow
+	// without the list would also trigger itself. A workflow_run event without a workflow, e.g. run by hand, is planned.
+	if t.Workflow != "" && len(filters.Workflows) == 0 {
+		tw.Info("no workflows are listed for workflow %s", t.Workflow)
+		return true, nil
+	}
+	if t.Workflow != "" && !slices.Contains(filters.Workflows, t.Workflow) {
+		tw.Info("workflow %s does not match %v", t.Workflow, filters.Workflows)
+		return true, nil
+	}
+
+	isTag := strings.HasPrefix(t.Ref, "refs/tags/")
+	hasBranchFilters := len(filters.Branches) > 0 || len(filters.BranchesIgnore) > 0
+	hasTagFilters := len(filters.Tags) > 0 || len(filters.TagsIgnore) > 0
+
+	// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#onpushbranchestagsbranches-ignoretags-ignore
+	switch {
+	case !hasBranchFilters && !hasTagFilters:
+	case t.Ref == "":
+		tw.Info("ref is unknown, ignoring branch and tag filters")
+	case isTag && !hasTagFilters:
+		tw.Info("%s is a tag, but only branch filters are defined", t.Ref)
+		return true, nil
+	case !isTag && !hasBranchFilters:
+		tw.Info("%s is not a tag, but only tag filters are defined", t.Ref)
+		return true, nil
+	case isTag:
+		if skip, err := skipByPatterns(filters.Tags, filters.TagsIgnore, tw, strings.TrimPrefix(t.Ref, "refs/tags/")); err != nil || skip {
+			return skip, err
+		}
+	default:
+		if skip, err := skipByPatterns(filters.Branches, filters.BranchesIgnore, tw, strings.TrimPrefix(t.Ref, "refs/heads/")); err != nil || skip {
+			return skip, err
+		}
+	}
+
+	// path filters are not evaluated for pushes of tags
+	if (len(filters.Paths) > 0 || len(filters.PathsIgnore) > 0) && !isTag {
+		if t.Paths == nil {
+			tw.Info("changed files are unknown, ignoring path filters")
+			return false, nil
+		}
+		return skipByPatterns(filters.Paths, filters.PathsIgnore, tw, t.Paths...)
+	}
+
+	return false, nil
+}
+
+func skipByPatterns(include []string, ignore []string, tw workflowpattern.TraceWriter, inputs ...string) (bool, error) {
+	if len(include) > 0 {
+		patterns, err := workflowpattern.CompilePatterns(include...)
+		if err != nil {
+			return false, err
+		}
+		return workflowpattern.Skip(patterns, inputs, tw), nil
+	}
+	patterns, err := workflowpattern.CompilePatterns(ignore...)
+	if err != nil {
+		return false, err
+	}
+	return workflowpattern.Filter(patterns, inputs, tw), nil
+}
+
+type plannerTraceWriter struct {
+	workflow *Workflow
+}
+
+func (tw *plannerTraceWriter) Info(format string, args ...interface{}) {
+	log.Debugf("%s: %s", tw.workflow.File, fmt.Sprintf(format, args...))
+}
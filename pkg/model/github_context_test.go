@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/nektos/act/pkg/common/git"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
@@ -210,3 +211,102 @@ func TestSetSha(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateEventPayload(t *testing.T) {
+	oldFindGitRef := findGitRef
+	oldFindGitRevision := findGitRevision
+	oldFindCommits := findCommits
+	oldFindDefaultBranch := findDefaultBranch
+	oldFindGithubRepo := findGithubRepo
+	oldFindChangedFiles := findChangedFiles
+	defer func() {
+		findGitRef = oldFindGitRef
+		findGitRevision = oldFindGitRevision
+		findCommits = oldFindCommits
+		findDefaultBranch = oldFindDefaultBranch
+		findGithubRepo = oldFindGithubRepo
+		findChangedFiles = oldFindChangedFiles
+	}()
+
+	headSha := "2222222222222222222222222222222222222222"
+	ref := "refs/heads/feature"
+	findGitRef = func(_ context.Context, _ string) (string, error) {
+		return ref, nil
+	}
+	findGitRevision = func(_ context.Context, _ string) (string, string, error) {
+		return headSha[:7], headSha, nil
+	}
+	findDefaultBranch = func(_ context.Context, _, _ string) (string, error) {
+		return "main", nil
+	}
+	findGithubRepo = func(_ context.Context, _, _, _ string) (string, error) {
+		return "owner/repo", nil
+	}
+	findChangedFiles = func(_ context.Context, _, _, _ string) ([]string, error) {
+		return []string{"README.md"}, nil
+	}
+	commits := map[string]git.Commit{
+		"main":        {SHA: "1111111111111111111111111111111111111111", Message: "base"},
+		headSha:       {SHA: headSha, Message: "feature\n\nbody", AuthorName: "author"},
+		headSha + "^": {SHA: "1111111111111111111111111111111111111111", Message: "base"},
+	}
+	findCommits = func(_ context.Context, _, base, head string) ([]git.Commit, error) {
+		if base == "main" {
+			return []git.Commit{commits[headSha]}, nil
+		}
+		if commit, ok := commits[head]; ok {
+			return []git.Commit{commit}, nil
+		}
+		return nil, fmt.Errorf("unknown revision %s", head)
+	}
+
+	generate := func(t *testing.T, eventName string) *GithubContext {
+		event, err := GenerateEventPayload(context.Background(), eventName, EventPayloadOptions{Actor: "actor", Inputs: map[string]string{"name": "value"}})
+		assert.NoError(t, err)
+		ghc := &GithubContext{EventName: eventName, Event: event}
+		ghc.SetRef(context.Background(), "", "/some/dir")
+		ghc.SetSha(context.Background(), "/some/dir")
+		ghc.SetBaseAndHeadRef()
+		return ghc
+	}
+
+	t.Run("push", func(t *testing.T) {
+		ghc := generate(t, "push")
+		assert.Equal(t, "refs/heads/feature", ghc.Ref)
+		assert.Equal(t, headSha, ghc.Sha)
+		assert.Equal(t, "1111111111111111111111111111111111111111", ghc.Event["before"])
+		assert.Equal(t, "feature\n\nbody", nestedMapLookup(ghc.Event, "head_commit", "message"))
+		assert.Len(t, ghc.Event["commits"], 1)
+		assert.Equal(t, "owner/repo", nestedMapLookup(ghc.Event, "repository", "full_name"))
+		assert.Equal(t, "actor", nestedMapLookup(ghc.Event, "sender", "login"))
+	})
+
+	t.Run("pull_request", func(t *testing.T) {
+		ghc := generate(t, "pull_request")
+		assert.Equal(t, "main", ghc.BaseRef)
+		assert.Equal(t, "feature", ghc.HeadRef)
+		assert.Equal(t, headSha, nestedMapLookup(ghc.Event, "pull_request", "head", "sha"))
+		assert.Equal(t, "1111111111111111111111111111111111111111", nestedMapLookup(ghc.Event, "pull_request", "base", "sha"))
+		assert.Equal(t, "feature", nestedMapLookup(ghc.Event, "pull_request", "title"))
+		assert.Equal(t, 1, nestedMapLookup(ghc.Event, "pull_request", "changed_files"))
+	})
+
+	t.Run("release", func(t *testing.T) {
+		ref = "refs/tags/v1.0.0"
+		defer func() { ref = "refs/heads/feature" }()
+		ghc := generate(t, "release")
+		assert.Equal(t, "refs/tags/v1.0.0", ghc.Ref)
+		assert.Equal(t, "published", ghc.Event["action"])
+	})
+
+	t.Run("workflow_dispatch", func(t *testing.T) {
+		ghc := generate(t, "workflow_dispatch")
+		assert.Equal(t, "refs/heads/feature", ghc.Ref)
+		assert.Equal(t, map[string]interface{}{"name": "value"}, ghc.Event["inputs"])
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := GenerateEventPayload(context.Background(), "issues", EventPayloadOptions{})
+		assert.ErrorIs(t, err, ErrUnsupportedEvent)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -16,9 +17,13 @@ import (
 // WorkflowPlanner contains methods for creating plans
 type WorkflowPlanner interface {
 	PlanEvent(eventName string) (*Plan, error)
+	PlanTrigger(trigger *EventTrigger) (*Plan, error)
+	PlanSchedule(at time.Time) (*Plan, error)
 	PlanJob(jobName string) (*Plan, error)
+	PlanWorkflowJobs(jobs map[string][]string) (*Plan, error)
 	PlanAll() (*Plan, error)
 	GetEvents() []string
+	GetScheduledRuns(from time.Time, limit int) ([]ScheduledRun, error)
 }
 
 // Plan contains a list of stages to run in series
@@ -199,6 +204,11 @@ type workflowPlanner struct {
 
 // PlanEvent builds a new list of runs to execute in parallel for an event name
 func (wp *workflowPlanner) PlanEvent(eventName string) (*Plan, error) {
+	return wp.PlanTrigger(&EventTrigger{Name: eventName})
+}
+
+// PlanTrigger builds a new list of runs to execute in parallel for an event, skipping workflows whose filters exclude it
+func (wp *workflowPlanner) PlanTrigger(trigger *EventTrigger) (*Plan, error) {
 	plan := new(Plan)
 	if len(wp.workflows) == 0 {
 		log.Debug("no workflows found by planner")
@@ -214,7 +224,20 @@ func (wp *workflowPlanner) PlanEvent(eventName string) (*Plan, error) {
 		}
 
 		for _, e := range events {
-			if e == eventName {
+			if e == trigger.Name {
+				skip, err := trigger.skip(w)
+				if err != nil {
+					err = fmt.Errorf("unable to evaluate the filters of %s (%s): %w", w.Name, w.File, err)
+					log.Warn(err)
+					lastErr = err
+					continue
+				}
+				if skip {
+					log.Infof("Skipping workflow '%s' (%s): its filters do not match the %s event", w.Name, w.File, trigger.Name)
+					continue
+				}
+				log.Debugf("Including workflow '%s' (%s) for the %s event", w.Name, w.File, trigger.Name)
+
 				stages, err := createStages(w, w.GetJobIDs()...)
 				if err != nil {
 					log.Warn(err)
@@ -228,6 +251,35 @@ func (wp *workflowPlanner) PlanEvent(eventName string) (*Plan, error) {
 	return plan, lastErr
 }
 
+// PlanSchedule builds a new list of runs to execute in parallel for the workflows whose schedules fire in the minute of at
+func (wp *workflowPlanner) PlanSchedule(at time.Time) (*Plan, error) {
+	plan := new(Plan)
+	var lastErr error
+
+	for _, w := range wp.workflows {
+		expr, ok, err := w.ScheduleAt(at)
+		if err != nil {
+			err = fmt.Errorf("unable to evaluate the schedule of %s (%s): %w", w.Name, w.File, err)
+			log.Warn(err)
+			lastErr = err
+			continue
+		}
+		if !ok {
+			continue
+		}
+		log.Debugf("Including workflow '%s' (%s), its schedule '%s' fires at %s", w.Name, w.File, expr, at.UTC().Format(time.RFC3339))
+
+		stages, err := createStages(w, w.GetJobIDs()...)
+		if err != nil {
+			log.Warn(err)
+			lastErr = err
+		} else {
+			plan.mergeStages(stages)
+		}
+	}
+	return plan, lastErr
+}
+
 // PlanJob builds a new run to execute in parallel for a job name
 func (wp *workflowPlanner) PlanJob(jobName string) (*Plan, error) {
 	plan := new(Plan)
@@ -248,6 +300,27 @@ func (wp *workflowPlanner) PlanJob(jobName string) (*Plan, error) {
 	return plan, lastErr
 }
 
+// PlanWorkflowJobs builds a new list of runs to execute in parallel for the jobs of the workflows by the file of their workflow
+func (wp *workflowPlanner) PlanWorkflowJobs(jobs map[string][]string) (*Plan, error) {
+	plan := new(Plan)
+	var lastErr error
+
+	for _, w := range wp.workflows {
+		jobIDs, ok := jobs[w.File]
+		if !ok {
+			continue
+		}
+		stages, err := createStages(w, jobIDs...)
+		if err != nil {
+			log.Warn(err)
+			lastErr = err
+		} else {
+			plan.mergeStages(stages)
+		}
+	}
+	return plan, lastErr
+}
+
 // PlanAll builds a new run to execute in parallel all
 func (wp *workflowPlanner) PlanAll() (*Plan, error) {
 	plan := new(Plan)
@@ -300,6 +373,11 @@ func (wp *workflowPlanner) GetEvents() []string {
 	return events
 }
 
+// GetScheduledRuns gets the next runs of the scheduled workflows after from
+func (wp *workflowPlanner) GetScheduledRuns(from time.Time, limit int) ([]ScheduledRun, error) {
+	return nextScheduledRuns(wp.workflows, from, limit)
+}
+
 // MaxRunNameLen determines the max name length of all jobs
 func (p *Plan) MaxRunNameLen() int {
 	maxRunNameLen := 0
@@ -1,11 +1,15 @@
 package model
 
 import (
+	"fmt"
 	"path/filepath"
+	"sort"
 	"testing"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type WorkflowPlanTest struct {
@@ -56,3 +60,126 @@ func TestWorkflow(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, result)
 }
+
+func TestPlanTrigger(t *testing.T) {
+	log.SetLevel(log.DebugLevel)
+
+	planner, err := NewWorkflowPlanner("testdata/event-filters", true, false)
+	assert.NoError(t, err)
+
+	tables := []struct {
+		name      string
+		trigger   *EventTrigger
+		workflows []string
+	}{
+		{"unknown-ref-and-paths", &EventTrigger{Name: "push"}, []string{"branches", "branches-ignore", "paths", "paths-ignore", "tags", "unfiltered"}},
+		{"push-main", &EventTrigger{Name: "push", Ref: "refs/heads/main"}, []string{"branches", "branches-ignore", "paths", "paths-ignore", "unfiltered"}},
+		{"push-release", &EventTrigger{Name: "push", Ref: "refs/heads/releases/1.0"}, []string{"branches", "paths", "paths-ignore", "unfiltered"}},
+		{"push-feature", &EventTrigger{Name: "push", Ref: "refs/heads/feature"}, []string{"branches-ignore", "paths", "paths-ignore", "unfiltered"}},
+		{"push-tag", &EventTrigger{Name: "push", Ref: "refs/tags/v1.0.0"}, []string{"paths", "paths-ignore", "tags", "unfiltered"}},
+		{"push-tag-excluded", &EventTrigger{Name: "push", Ref: "refs/tags/v1.0.0-rc1"}, []string{"paths", "paths-ignore", "unfiltered"}},
+		{"push-docs", &EventTrigger{Name: "push", Ref: "refs/heads/main", Paths: []string{"docs/index.md"}}, []string{"branches", "branches-ignore", "paths", "unfiltered"}},
+		{"push-code", &EventTrigger{Name: "push", Ref: "refs/heads/main", Paths: []string{"main.go", "README.md"}}, []string{"branches", "branches-ignore", "paths-ignore", "unfiltered"}},
+		{"push-nothing", &EventTrigger{Name: "push", Ref: "refs/heads/main", Paths: []string{}}, []string{"branches", "branches-ignore", "unfiltered"}},
+		{"pull-request-main", &EventTrigger{Name: "pull_request", Ref: "refs/heads/main", Paths: []string{"main.go"}}, []string{"branches", "unfiltered"}},
+		{"pull-request-other", &EventTrigger{Name: "pull_request", Ref: "refs/heads/develop", Paths: []string{"docs/index.md"}}, []string{"paths", "unfiltered"}},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			plan, err := planner.PlanTrigger(table.trigger)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, table.workflows, planWorkflowNames(plan))
+		})
+	}
+}
+
+func TestPlanTriggerActivityTypes(t *testing.T) {
+	log.SetLevel(log.DebugLevel)
+
+	planner, err := NewWorkflowPlanner("testdata/event-types", true, false)
+	assert.NoError(t, err)
+
+	tables := []struct {
+		name      string
+		trigger   *EventTrigger
+		workflows []string
+	}{
+		{"pull-request-unknown-action", &EventTrigger{Name: "pull_request"}, []string{"labeled", "pull-request"}},
+		{"pull-request-opened", &EventTrigger{Name: "pull_request", Action: "opened"}, []string{"pull-request"}},
+		{"pull-request-labeled", &EventTrigger{Name: "pull_request", Action: "labeled"}, []string{"labeled"}},
+		{"pull-request-closed", &EventTrigger{Name: "pull_request", Action: "closed"}, []string{}},
+		{"release-published", &EventTrigger{Name: "release", Action: "published"}, []string{"release", "release-all"}},
+		{"release-created", &EventTrigger{Name: "release", Action: "created"}, []string{"release-all"}},
+		{"workflow-run-build", &EventTrigger{Name: "workflow_run", Action: "completed", Workflow: "build"}, []string{"workflow-run"}},
+		{"workflow-run-requested", &EventTrigger{Name: "workflow_run", Action: "requested", Workflow: "build"}, []string{}},
+		{"workflow-run-other", &EventTrigger{Name: "workflow_run", Action: "completed", Workflow: "lint"}, []string{}},
+		{"workflow-run-self", &EventTrigger{Name: "workflow_run", Action: "completed", Workflow: "workflow-run-unfiltered"}, []string{}},
+		{"workflow-run-direct", &EventTrigger{Name: "workflow_run"}, []string{"workflow-run", "workflow-run-unfiltered"}},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			plan, err := planner.PlanTrigger(table.trigger)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, table.workflows, planWorkflowNames(plan))
+		})
+	}
+}
+
+func planWorkflowNames(plan *Plan) []string {
+	workflows := []string{}
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			workflows = append(workflows, run.Workflow.Name)
+		}
+	}
+	return workflows
+}
+
+func TestPlanSchedule(t *testing.T) {
+	planner, err := NewWorkflowPlanner("testdata/schedule", true, false)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		at        string
+		workflows []string
+	}{
+		// a monday
+		{"2024-01-01T03:00:00Z", []string{"nightly", "weekdays"}},
+		{"2024-01-01T03:00:59Z", []string{"nightly", "weekdays"}},
+		{"2024-01-02T03:00:00Z", []string{"nightly"}},
+		{"2024-01-02T12:30:00Z", []string{"weekdays"}},
+		{"2024-01-06T12:30:00Z", []string{}},
+		{"2024-01-02T04:00:00Z", []string{}},
+	} {
+		at, err := time.Parse(time.RFC3339, tt.at)
+		require.NoError(t, err)
+		plan, err := planner.PlanSchedule(at)
+		require.NoError(t, err)
+		workflows := planWorkflowNames(plan)
+		sort.Strings(workflows)
+		assert.Equal(t, tt.workflows, workflows, tt.at)
+	}
+}
+
+func TestGetScheduledRuns(t *testing.T) {
+	planner, err := NewWorkflowPlanner("testdata/schedule", true, false)
+	require.NoError(t, err)
+
+	from, err := time.Parse(time.RFC3339, "2024-01-01T12:00:00Z")
+	require.NoError(t, err)
+	runs, err := planner.GetScheduledRuns(from, 4)
+	require.NoError(t, err)
+
+	actual := []string{}
+	for _, run := range runs {
+		actual = append(actual, fmt.Sprintf("%s %s %s", run.Time.Format(time.RFC3339), run.Workflow.Name, run.Cron))
+	}
+	assert.Equal(t, []string{
+		"2024-01-01T12:30:00Z weekdays 30 12 * * 1-5",
+		"2024-01-02T03:00:00Z nightly 0 3 * * *",
+		"2024-01-02T12:30:00Z weekdays 30 12 * * 1-5",
+		"2024-01-03T03:00:00Z nightly 0 3 * * *",
+	}, actual)
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ScheduledRun is a time at which a cron expression of the schedule event of a workflow fires
+type ScheduledRun struct {
+	Workflow *Workflow
+	Cron     string
+	Time     time.Time
+}
+
+// parseCron parses a POSIX cron expression, which GitHub evaluates in UTC
+func parseCron(expr string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression '%s': %w", expr, err)
+	}
+	return schedule, nil
+}
+
+// ScheduleAt returns the cron expression of the workflow which fires in the minute of t
+func (w *Workflow) ScheduleAt(t time.Time) (string, bool, error) {
+	minute := t.UTC().Truncate(time.Minute)
+	for _, expr := range w.Schedules() {
+		schedule, err := parseCron(expr)
+		if err != nil {
+			return "", false, err
+		}
+		if schedule.Next(minute.Add(-time.Second)).Equal(minute) {
+			return expr, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// nextScheduledRuns returns the runs of the workflows scheduled after from, in chronological order
+func nextScheduledRuns(workflows []*Workflow, from time.Time, limit int) ([]ScheduledRun, error) {
+	type next struct {
+		ScheduledRun
+		schedule cron.Schedule
+	}
+	nexts := []*next{}
+	var errs []error
+	for _, w := range workflows {
+		for _, expr := range w.Schedules() {
+			schedule, err := parseCron(expr)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s (%s): %w", w.Name, w.File, err))
+				continue
+			}
+			t := schedule.Next(from.UTC())
+			if t.IsZero() {
+				continue
+			}
+			nexts = append(nexts, &next{ScheduledRun{w, expr, t}, schedule})
+		}
+	}
+
+	runs := []ScheduledRun{}
+	for len(runs) < limit && len(nexts) > 0 {
+		sort.SliceStable(nexts, func(i, j int) bool {
+			return nexts[i].Time.Before(nexts[j].Time)
+		})
+		n := nexts[0]
+		runs = append(runs, n.ScheduledRun)
+		n.Time = n.schedule.Next(n.Time)
+	}
+	return runs, errors.Join(errs...)
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"reflect"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -17,12 +19,13 @@ import (
 
 // Workflow is the structure of the files in .github/workflows
 type Workflow struct {
-	File     string
-	Name     string            `yaml:"name"`
-	RawOn    yaml.Node         `yaml:"on"`
-	Env      map[string]string `yaml:"env"`
-	Jobs     map[string]*Job   `yaml:"jobs"`
-	Defaults Defaults          `yaml:"defaults"`
+	File           string
+	Name           string            `yaml:"name"`
+	RawOn          yaml.Node         `yaml:"on"`
+	Env            map[string]string `yaml:"env"`
+	Jobs           map[string]*Job   `yaml:"jobs"`
+	Defaults       Defaults          `yaml:"defaults"`
+	RawConcurrency yaml.Node         `yaml:"concurrency"`
 }
 
 // On events for the workflow
@@ -57,6 +60,11 @@ func (w *Workflow) On() []string {
 	return nil
 }
 
+// Concurrency returns the concurrency group of the workflow, or nil if the workflow has none
+func (w *Workflow) Concurrency() *Concurrency {
+	return concurrency(w.RawConcurrency)
+}
+
 func (w *Workflow) OnEvent(event string) interface{} {
 	if w.RawOn.Kind == yaml.MappingNode {
 		var val map[string]interface{}
@@ -68,6 +76,51 @@ func (w *Workflow) OnEvent(event string) interface{} {
 	return nil
 }
 
+// EventFilters are the filters configured for a single event of the `on` key
+type EventFilters struct {
+	Branches       []string
+	BranchesIgnore []string
+	Tags           []string
+	TagsIgnore     []string
+	Paths          []string
+	PathsIgnore    []string
+	Types          []string
+	Workflows      []string // names of the workflows which trigger a workflow_run event
+}
+
+// EventFilters returns the filters configured for an event, or nil if the event has no configuration
+func (w *Workflow) EventFilters(event string) *EventFilters {
+	if w.RawOn.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var val map[string]yaml.Node
+	if !decodeNode(w.RawOn, &val) {
+		return nil
+	}
+
+	node, found := val[event]
+	if !found || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var raw map[string]yaml.Node
+	if !decodeNode(node, &raw) {
+		return nil
+	}
+
+	return &EventFilters{
+		Branches:       nodeAsStringSlice(raw["branches"]),
+		BranchesIgnore: nodeAsStringSlice(raw["branches-ignore"]),
+		Tags:           nodeAsStringSlice(raw["tags"]),
+		TagsIgnore:     nodeAsStringSlice(raw["tags-ignore"]),
+		Paths:          nodeAsStringSlice(raw["paths"]),
+		PathsIgnore:    nodeAsStringSlice(raw["paths-ignore"]),
+		Types:          nodeAsStringSlice(raw["types"]),
+		Workflows:      nodeAsStringSlice(raw["workflows"]),
+	}
+}
+
 func (w *Workflow) UnmarshalYAML(node *yaml.Node) error {
 	// Resolve yaml anchor aliases first
 	if err := resolveAliases(node); err != nil {
@@ -114,6 +167,72 @@ type WorkflowDispatch struct {
 	Inputs map[string]WorkflowDispatchInput `yaml:"inputs"`
 }
 
+// Coerce validates the value of an input against its type and converts it to the type it has in the inputs context.
+// The value of an environment input has to be one of the given environments, unless none are known.
+func (input WorkflowDispatchInput) Coerce(value string, environments []string) (interface{}, error) {
+	switch input.Type {
+	case "boolean":
+		switch value {
+		case "", "false":
+			return false, nil
+		case "true":
+			return true, nil
+		}
+		return nil, fmt.Errorf("'%s' is not a boolean, expected true or false", value)
+	case "number":
+		if value == "" {
+			return value, nil
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", value)
+		}
+		return number, nil
+	case "choice":
+		if value != "" && !slices.Contains(input.Options, value) {
+			return nil, fmt.Errorf("'%s' is not one of the options %v", value, input.Options)
+		}
+	case "environment":
+		// the names of environments are case insensitive
+		if value != "" && len(environments) > 0 && !slices.ContainsFunc(environments, func(environment string) bool {
+			return strings.EqualFold(environment, value)
+		}) {
+			return nil, fmt.Errorf("'%s' is not one of the environments %v", value, environments)
+		}
+	}
+	return value, nil
+}
+
+// ResolveInputs validates the provided inputs against the declared inputs and returns the inputs context.
+// Missing inputs fall back to their default. The returned inputs are complete even if some are invalid.
+func (w *WorkflowDispatch) ResolveInputs(provided map[string]interface{}, environments []string) (map[string]interface{}, error) {
+	names := make([]string, 0, len(w.Inputs))
+	for name := range w.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	inputs := map[string]interface{}{}
+	var errs []error
+	for _, name := range names {
+		input := w.Inputs[name]
+		value := input.Default
+		if v, ok := provided[name]; ok && v != nil {
+			value = fmt.Sprint(v)
+		}
+		if value == "" && input.Required && input.Type != "boolean" {
+			errs = append(errs, fmt.Errorf("input '%s' is required", name))
+		}
+		coerced, err := input.Coerce(value, environments)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("input '%s': %w", name, err))
+			coerced = value
+		}
+		inputs[name] = coerced
+	}
+	return inputs, errors.Join(errs...)
+}
+
 func (w *Workflow) WorkflowDispatchConfig() *WorkflowDispatch {
 	switch w.RawOn.Kind {
 	case yaml.ScalarNode:
@@ -151,6 +270,34 @@ func (w *Workflow) WorkflowDispatchConfig() *WorkflowDispatch {
 	return nil
 }
 
+// Schedules returns the cron expressions of the schedule event of the workflow
+func (w *Workflow) Schedules() []string {
+	if w.RawOn.Kind != yaml.MappingNode {
+		return nil
+	}
+	var val map[string]yaml.Node
+	if !decodeNode(w.RawOn, &val) {
+		return nil
+	}
+	node, ok := val["schedule"]
+	if !ok {
+		return nil
+	}
+	var schedules []struct {
+		Cron string `yaml:"cron"`
+	}
+	if !decodeNode(node, &schedules) {
+		return nil
+	}
+	crons := make([]string, 0, len(schedules))
+	for _, schedule := range schedules {
+		if schedule.Cron != "" {
+			crons = append(crons, schedule.Cron)
+		}
+	}
+	return crons
+}
+
 type WorkflowCallInput struct {
 	Description string    `yaml:"description"`
 	Required    bool      `yaml:"required"`
@@ -194,22 +341,26 @@ func (w *Workflow) WorkflowCallConfig() *WorkflowCall {
 
 // Job is the structure of one job in a workflow
 type Job struct {
-	Name           string                    `yaml:"name"`
-	RawNeeds       yaml.Node                 `yaml:"needs"`
-	RawRunsOn      yaml.Node                 `yaml:"runs-on"`
-	Env            yaml.Node                 `yaml:"env"`
-	If             yaml.Node                 `yaml:"if"`
-	Steps          []*Step                   `yaml:"steps"`
-	TimeoutMinutes string                    `yaml:"timeout-minutes"`
-	Services       map[string]*ContainerSpec `yaml:"services"`
-	Strategy       *Strategy                 `yaml:"strategy"`
-	RawContainer   yaml.Node                 `yaml:"container"`
-	Defaults       Defaults                  `yaml:"defaults"`
-	Outputs        map[string]string         `yaml:"outputs"`
-	Uses           string                    `yaml:"uses"`
-	With           map[string]interface{}    `yaml:"with"`
-	RawSecrets     yaml.Node                 `yaml:"secrets"`
-	Result         string
+	Name               string                    `yaml:"name"`
+	RawNeeds           yaml.Node                 `yaml:"needs"`
+	RawRunsOn          yaml.Node                 `yaml:"runs-on"`
+	Env                yaml.Node                 `yaml:"env"`
+	If                 yaml.Node                 `yaml:"if"`
+	Steps              []*Step                   `yaml:"steps"`
+	TimeoutMinutes     string                    `yaml:"timeout-minutes"`
+	RawContinueOnError string                    `yaml:"continue-on-error"`
+	Services           map[string]*ContainerSpec `yaml:"services"`
+	Strategy           *Strategy                 `yaml:"strategy"`
+	RawContainer       yaml.Node                 `yaml:"container"`
+	Defaults           Defaults                  `yaml:"defaults"`
+	Outputs            map[string]string         `yaml:"outputs"`
+	Uses               string                    `yaml:"uses"`
+	With               map[string]interface{}    `yaml:"with"`
+	RawSecrets         yaml.Node                 `yaml:"secrets"`
+	RawConcurrency     yaml.Node                 `yaml:"concurrency"`
+	RawEnvironment     yaml.Node                 `yaml:"environment"`
+	Result             string
+	ContinuedOnError   bool // the job failed, but continue-on-error keeps the workflow run from failing
 }
 
 // Strategy for the job
@@ -221,6 +372,35 @@ type Strategy struct {
 	RawMatrix         yaml.Node `yaml:"matrix"`
 }
 
+// DeploymentEnvironment is the environment a job deploys to
+type DeploymentEnvironment struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+// Concurrency ensures that only a single workflow or job of a concurrency group runs at a time
+type Concurrency struct {
+	Group            string `yaml:"group"`
+	CancelInProgress string `yaml:"cancel-in-progress"`
+}
+
+func concurrency(node yaml.Node) *Concurrency {
+	var val *Concurrency
+	switch node.Kind {
+	case yaml.ScalarNode:
+		val = new(Concurrency)
+		if !decodeNode(node, &val.Group) {
+			return nil
+		}
+	case yaml.MappingNode:
+		val = new(Concurrency)
+		if !decodeNode(node, val) {
+			return nil
+		}
+	}
+	return val
+}
+
 // Default settings that will apply to all steps in the job or workflow
 type Defaults struct {
 	Run RunDefaults `yaml:"run"`
@@ -307,6 +487,34 @@ func (j *Job) Container() *ContainerSpec {
 	return val
 }
 
+// Concurrency returns the concurrency group of the job, or nil if the job has none
+func (j *Job) Concurrency() *Concurrency {
+	return concurrency(j.RawConcurrency)
+}
+
+// DeploymentEnvironment returns the deployment environment of the job, or nil if the job has none
+func (j *Job) DeploymentEnvironment() *DeploymentEnvironment {
+	var val *DeploymentEnvironment
+	switch j.RawEnvironment.Kind {
+	case yaml.ScalarNode:
+		val = new(DeploymentEnvironment)
+		if !decodeNode(j.RawEnvironment, &val.Name) {
+			return nil
+		}
+	case yaml.MappingNode:
+		val = new(DeploymentEnvironment)
+		if !decodeNode(j.RawEnvironment, val) {
+			return nil
+		}
+	}
+	return val
+}
+
+// Failed reports whether the job failed without continue-on-error
+func (j *Job) Failed() bool {
+	return j.Result == "failure" && !j.ContinuedOnError
+}
+
 // Needs list for Job
 func (j *Job) Needs() []string {
 	switch j.RawNeeds.Kind {
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+// WorkflowRun is the outcome of a workflow of an executed plan, which triggers the workflow_run event
+type WorkflowRun struct {
+	Workflow   *Workflow
+	Conclusion string // success, failure, cancelled or skipped
+}
+
+// WorkflowRuns returns the outcome of each workflow of the plan after the plan was executed, in the order the workflows were planned
+func (p *Plan) WorkflowRuns() []WorkflowRun {
+	runs := []WorkflowRun{}
+	index := map[*Workflow]int{}
+	skipped := map[*Workflow]bool{}
+	for _, stage := range p.Stages {
+		for _, run := range stage.Runs {
+			i, ok := index[run.Workflow]
+			if !ok {
+				i = len(runs)
+				index[run.Workflow] = i
+				runs = append(runs, WorkflowRun{Workflow: run.Workflow, Conclusion: "success"})
+				skipped[run.Workflow] = true
+			}
+			job := run.Job()
+			skipped[run.Workflow] = skipped[run.Workflow] && job.Result == "skipped"
+			switch {
+			case job.Failed():
+				runs[i].Conclusion = "failure"
+			case job.Result == "cancelled" && runs[i].Conclusion != "failure":
+				runs[i].Conclusion = "cancelled"
+			}
+		}
+	}
+	for i := range runs {
+		if skipped[runs[i].Workflow] {
+			runs[i].Conclusion = "skipped"
+		}
+	}
+	return runs
+}
+
+// NewWorkflowRunEvent synthesizes the payload of the workflow_run event sent when a workflow completed.
+// The head of the run is taken from the payload of the event which triggered the workflow, falling back to the local git repository.
+func NewWorkflowRunEvent(ctx context.Context, run WorkflowRun, eventName string, event map[string]interface{}, repoPath string) map[string]interface{} {
+	logger := common.Logger(ctx)
+
+	var headSha, headBranch string
+	switch eventName {
+	case "push":
+		headSha = asString(event["after"])
+		headBranch = shortRefName(asString(event["ref"]))
+	case "pull_request", "pull_request_target":
+		headSha = asString(nestedMapLookup(event, "pull_request", "head", "sha"))
+		headBranch = asString(nestedMapLookup(event, "pull_request", "head", "ref"))
+	case "workflow_run":
+		// downstream workflows of a chain share the head of the first workflow
+		headSha = asString(nestedMapLookup(event, "workflow_run", "head_sha"))
+		headBranch = asString(nestedMapLookup(event, "workflow_run", "head_branch"))
+	}
+	if headSha == "" || isZeroSha(headSha) {
+		var err error
+		if _, headSha, err = findGitRevision(ctx, repoPath); err != nil {
+			logger.Debugf("unable to get the git revision: %v", err)
+		}
+	}
+	if headBranch == "" {
+		if ref, err := findGitRef(ctx, repoPath); err != nil {
+			logger.Debugf("unable to get the git ref: %v", err)
+		} else {
+			headBranch = shortRefName(ref)
+		}
+	}
+
+	payload := map[string]interface{}{
+		"action": "completed",
+		"workflow": map[string]interface{}{
+			"name": run.Workflow.Name,
+			"path": ".github/workflows/" + run.Workflow.File,
+		},
+		"workflow_run": map[string]interface{}{
+			"name":        run.Workflow.Name,
+			"path":        ".github/workflows/" + run.Workflow.File,
+			"event":       eventName,
+			"status":      "completed",
+			"conclusion":  run.Conclusion,
+			"head_sha":    headSha,
+			"head_branch": headBranch,
+			"run_attempt": 1,
+		},
+	}
+	for _, key := range []string{"repository", "sender"} {
+		if value, ok := event[key]; ok {
+			payload[key] = value
+		}
+	}
+	return payload
+}
+
+// shortRefName returns the name of the branch or tag of a ref, GitHub reports tags as head branches too
+func shortRefName(ref string) string {
+	if tag, ok := strings.CutPrefix(ref, "refs/tags/"); ok {
+		return tag
+	}
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
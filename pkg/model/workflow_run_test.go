@@ -0,0 +1,94 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanWorkflowRuns(t *testing.T) {
+	build := &Workflow{Name: "build", Jobs: map[string]*Job{"a": {}, "b": {}}}
+	lint := &Workflow{Name: "lint", Jobs: map[string]*Job{"a": {}}}
+	plan := &Plan{Stages: []*Stage{
+		{Runs: []*Run{{Workflow: build, JobID: "a"}, {Workflow: lint, JobID: "a"}}},
+		{Runs: []*Run{{Workflow: build, JobID: "b"}}},
+	}}
+
+	tables := []struct {
+		name        string
+		results     [3]string // results of build/a, build/b and lint/a
+		conclusions []string
+	}{
+		{"success", [3]string{"success", "success", "success"}, []string{"success", "success"}},
+		{"failure", [3]string{"failure", "cancelled", "skipped"}, []string{"failure", "skipped"}},
+		{"cancelled", [3]string{"success", "cancelled", "success"}, []string{"cancelled", "success"}},
+		{"partly-skipped", [3]string{"skipped", "success", "success"}, []string{"success", "success"}},
+		{"continued-on-error", [3]string{"success", "failure", "success"}, []string{"success", "success"}},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			build.Jobs["a"].Result = table.results[0]
+			build.Jobs["b"].Result = table.results[1]
+			build.Jobs["b"].ContinuedOnError = table.name == "continued-on-error"
+			lint.Jobs["a"].Result = table.results[2]
+
+			runs := plan.WorkflowRuns()
+			assert.Equal(t, []WorkflowRun{{build, table.conclusions[0]}, {lint, table.conclusions[1]}}, runs)
+		})
+	}
+}
+
+func TestNewWorkflowRunEvent(t *testing.T) {
+	oldFindGitRef := findGitRef
+	oldFindGitRevision := findGitRevision
+	defer func() { findGitRef = oldFindGitRef }()
+	defer func() { findGitRevision = oldFindGitRevision }()
+
+	findGitRef = func(_ context.Context, _ string) (string, error) {
+		return "refs/heads/local", nil
+	}
+	findGitRevision = func(_ context.Context, _ string) (string, string, error) {
+		return "", "1234fakesha", nil
+	}
+
+	run := WorkflowRun{&Workflow{Name: "build", File: "build.yml"}, "failure"}
+	repository := map[string]interface{}{"full_name": "nektos/act"}
+
+	tables := []struct {
+		name       string
+		eventName  string
+		event      map[string]interface{}
+		headSha    string
+		headBranch string
+	}{
+		{"push", "push", map[string]interface{}{"ref": "refs/heads/main", "after": "abc", "repository": repository}, "abc", "main"},
+		{"push-tag", "push", map[string]interface{}{"ref": "refs/tags/v1.0.0", "after": "abc", "repository": repository}, "abc", "v1.0.0"},
+		{"pull-request", "pull_request", map[string]interface{}{"pull_request": map[string]interface{}{"head": map[string]interface{}{"ref": "feature", "sha": "def"}}}, "def", "feature"},
+		{"workflow-run", "workflow_run", map[string]interface{}{"workflow_run": map[string]interface{}{"head_branch": "main", "head_sha": "abc"}}, "abc", "main"},
+		{"local", "workflow_dispatch", map[string]interface{}{}, "1234fakesha", "local"},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			event := NewWorkflowRunEvent(context.Background(), run, table.eventName, table.event, "")
+			assert.Equal(t, "completed", event["action"])
+			assert.Equal(t, table.event["repository"], event["repository"])
+			assert.Equal(t, map[string]interface{}{
+				"name":        "build",
+				"path":        ".github/workflows/build.yml",
+				"event":       table.eventName,
+				"status":      "completed",
+				"conclusion":  "failure",
+				"head_sha":    table.headSha,
+				"head_branch": table.headBranch,
+				"run_attempt": 1,
+			}, event["workflow_run"])
+
+			trigger := NewEventTrigger(context.Background(), "workflow_run", event, "", "")
+			assert.Equal(t, "build", trigger.Workflow)
+			assert.Equal(t, "completed", trigger.Action)
+		})
+	}
+}
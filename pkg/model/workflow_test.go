@@ -136,6 +136,64 @@ jobs:
 	assert.Contains(t, workflow.Jobs["test2"].Container().Env["foo"], "bar")
 }
 
+func TestReadWorkflow_Concurrency(t *testing.T) {
+	yaml := `
+name: concurrency
+on: push
+concurrency: deploy-${{ github.ref }}
+
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    concurrency:
+      group: test-${{ matrix.os }}
+      cancel-in-progress: true
+    steps:
+    - run: echo
+  test2:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+`
+
+	workflow, err := ReadWorkflow(strings.NewReader(yaml), false)
+	assert.NoError(t, err, "read workflow should succeed")
+	assert.Equal(t, &Concurrency{Group: "deploy-${{ github.ref }}"}, workflow.Concurrency())
+	assert.Equal(t, &Concurrency{Group: "test-${{ matrix.os }}", CancelInProgress: "true"}, workflow.Jobs["test"].Concurrency())
+	assert.Nil(t, workflow.Jobs["test2"].Concurrency())
+}
+
+func TestReadWorkflow_DeploymentEnvironment(t *testing.T) {
+	yaml := `
+name: environment
+on: push
+
+jobs:
+  staging:
+    runs-on: ubuntu-latest
+    environment: staging
+    steps:
+    - run: echo
+  production:
+    runs-on: ubuntu-latest
+    environment:
+      name: production
+      url: ${{ steps.deploy.outputs.url }}
+    steps:
+    - run: echo
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+`
+
+	workflow, err := ReadWorkflow(strings.NewReader(yaml), false)
+	assert.NoError(t, err, "read workflow should succeed")
+	assert.Equal(t, &DeploymentEnvironment{Name: "staging"}, workflow.Jobs["staging"].DeploymentEnvironment())
+	assert.Equal(t, &DeploymentEnvironment{Name: "production", URL: "${{ steps.deploy.outputs.url }}"}, workflow.Jobs["production"].DeploymentEnvironment())
+	assert.Nil(t, workflow.Jobs["test"].DeploymentEnvironment())
+}
+
 func TestReadWorkflow_ObjectContainer(t *testing.T) {
 	yaml := `
 name: local-action-docker-url
@@ -612,3 +670,66 @@ on: push #*trigger
 		assert.Equal(t, "actions/checkout@v5", job.Steps[0].Uses)
 	}
 }
+
+func TestWorkflowDispatchResolveInputs(t *testing.T) {
+	config := &WorkflowDispatch{
+		Inputs: map[string]WorkflowDispatchInput{
+			"name":   {Required: true},
+			"greet":  {Type: "boolean", Default: "true"},
+			"count":  {Type: "number", Default: "1"},
+			"level":  {Type: "choice", Options: []string{"info", "debug"}, Default: "info"},
+			"target": {Type: "environment"},
+			"region": {Type: "environment", Required: true, Default: "eu"},
+		},
+	}
+	environments := []string{"eu", "production", "staging"}
+
+	for name, tt := range map[string]struct {
+		provided map[string]interface{}
+		inputs   map[string]interface{}
+		errs     []string
+	}{
+		"defaults": {
+			provided: map[string]interface{}{"name": "act"},
+			inputs:   map[string]interface{}{"name": "act", "greet": true, "count": 1.0, "level": "info", "target": "", "region": "eu"},
+		},
+		"coerced": {
+			provided: map[string]interface{}{"name": "act", "greet": false, "count": "2.5", "level": "debug", "target": "Production"},
+			inputs:   map[string]interface{}{"name": "act", "greet": false, "count": 2.5, "level": "debug", "target": "Production", "region": "eu"},
+		},
+		"invalid": {
+			provided: map[string]interface{}{"greet": "yes", "count": "many", "level": "trace", "target": "qa", "region": ""},
+			inputs:   map[string]interface{}{"name": "", "greet": "yes", "count": "many", "level": "trace", "target": "qa", "region": ""},
+			errs: []string{
+				"input 'name' is required",
+				"input 'greet': 'yes' is not a boolean, expected true or false",
+				"input 'count': 'many' is not a number",
+				"input 'level': 'trace' is not one of the options [info debug]",
+				"input 'target': 'qa' is not one of the environments [eu production staging]",
+				"input 'region' is required",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			inputs, err := config.ResolveInputs(tt.provided, environments)
+			assert.Equal(t, tt.inputs, inputs)
+			if len(tt.errs) == 0 {
+				assert.NoError(t, err)
+			}
+			for _, e := range tt.errs {
+				assert.ErrorContains(t, err, e)
+			}
+		})
+	}
+}
+
+func TestWorkflowDispatchInputCoerceEnvironment(t *testing.T) {
+	input := WorkflowDispatchInput{Type: "environment"}
+
+	value, err := input.Coerce("qa", nil)
+	assert.NoError(t, err, "any environment is accepted when none are known")
+	assert.Equal(t, "qa", value)
+
+	_, err = input.Coerce("qa", []string{"production"})
+	assert.EqualError(t, err, "'qa' is not one of the environments [production]")
+}
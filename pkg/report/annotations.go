@@ -0,0 +1,104 @@
+// Package report writes the reports of a run of act from the records of its jobs
+package report
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sync"
+
+	"github.com/nektos/act/pkg/runner"
+)
+
+// Collector keeps the records of the jobs of a run, its RecordJob is a runner.JobRecorder
+type Collector struct {
+	mu      sync.Mutex
+	records []runner.JobRecord
+}
+
+// RecordJob adds the record of a completed job
+func (c *Collector) RecordJob(record runner.JobRecord) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.records = append(c.records, record)
+}
+
+// Records returns the records of the jobs in the order they completed
+func (c *Collector) Records() []runner.JobRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]runner.JobRecord{}, c.records...)
+}
+
+// Annotation is an annotation of a job of a run
+type Annotation struct {
+	runner.Annotation
+	Workflow string // path of the workflow file, relative to the working directory
+	JobID    string
+	Job      string // name of the job, including its matrix
+}
+
+// Annotations returns the annotations of the jobs, workflowsDir is the path of the directory of the workflows
+func Annotations(records []runner.JobRecord, workflowsDir string) []Annotation {
+	annotations := []Annotation{}
+	for _, record := range records {
+		for _, annotation := range record.Annotations {
+			annotations = append(annotations, Annotation{
+				Annotation: annotation,
+				Workflow:   path.Join(workflowsDir, record.Workflow),
+				JobID:      record.JobID,
+				Job:        record.Name,
+			})
+		}
+	}
+	return annotations
+}
+
+// location returns file:line:column of an annotation, empty if it has no file
+func (a Annotation) location() string {
+	location := a.File
+	if location != "" && a.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, a.Line)
+		if a.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, a.Column)
+		}
+	}
+	return location
+}
+
+// WriteAnnotationSummary writes the annotations grouped by job
+func WriteAnnotationSummary(w io.Writer, annotations []Annotation) {
+	counts := map[string]int{}
+	jobs := []string{}
+	byJob := map[string][]Annotation{}
+	for _, annotation := range annotations {
+		counts[annotation.Level]++
+		if _, ok := byJob[annotation.Job]; !ok {
+			jobs = append(jobs, annotation.Job)
+		}
+		byJob[annotation.Job] = append(byJob[annotation.Job], annotation)
+	}
+
+	fmt.Fprintf(w, "Annotations: %s\n", countSummary(counts))
+	for _, job := range jobs {
+		fmt.Fprintf(w, "\n%s\n", job)
+		for _, annotation := range byJob[job] {
+			fmt.Fprintf(w, "  %-7s ", annotation.Level)
+			if location := annotation.location(); location != "" {
+				fmt.Fprintf(w, "%s: ", location)
+			}
+			if annotation.Title != "" {
+				fmt.Fprintf(w, "%s: ", annotation.Title)
+			}
+			fmt.Fprint(w, annotation.Message)
+			if annotation.Step != "" {
+				fmt.Fprintf(w, " (%s)", annotation.Step)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+func countSummary(counts map[string]int) string {
+	return fmt.Sprintf("%d error(s), %d warning(s), %d notice(s)", counts["error"], counts["warning"], counts["notice"])
+}
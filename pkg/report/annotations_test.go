@@ -0,0 +1,38 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nektos/act/pkg/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteAnnotationSummary(t *testing.T) {
+	records := []runner.JobRecord{
+		{Workflow: "ci.yml", JobID: "build", Name: "build (1.22)", Annotations: []runner.Annotation{
+			{Level: "error", File: "main.go", Line: 3, Column: 5, Title: "vet", Message: "unreachable code", Step: "go vet"},
+		}},
+		{Workflow: "ci.yml", JobID: "lint", Name: "lint"},
+		{Workflow: "ci.yml", JobID: "build", Name: "build (1.23)", Annotations: []runner.Annotation{
+			{Level: "warning", Message: "deprecated input"},
+			{Level: "notice", File: "README.md", Message: "outdated section", Step: "docs"},
+		}},
+	}
+
+	annotations := Annotations(records, ".github/workflows")
+	assert.Len(t, annotations, 3)
+	assert.Equal(t, ".github/workflows/ci.yml", annotations[0].Workflow)
+
+	buf := &bytes.Buffer{}
+	WriteAnnotationSummary(buf, annotations)
+	assert.Equal(t, `Annotations: 1 error(s), 1 warning(s), 1 notice(s)
+
+build (1.22)
+  error   main.go:3:5: vet: unreachable code (go vet)
+
+build (1.23)
+  warning deprecated input
+  notice  README.md: outdated section (docs)
+`, buf.String())
+}
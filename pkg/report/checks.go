@@ -0,0 +1,76 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// checkOutput is the output of a check run of the GitHub Checks API
+// see https://docs.github.com/en/rest/checks/runs#create-a-check-run
+type checkOutput struct {
+	Title       string            `json:"title"`
+	Summary     string            `json:"summary"`
+	Annotations []checkAnnotation `json:"annotations"`
+}
+
+type checkAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	StartColumn     int    `json:"start_column,omitempty"`
+	EndColumn       int    `json:"end_column,omitempty"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+	RawDetails      string `json:"raw_details,omitempty"`
+}
+
+// WriteChecks writes the annotations as the output of a check run, annotations without a file are reported on the workflow
+func WriteChecks(w io.Writer, annotations []Annotation) error {
+	counts := map[string]int{}
+	checkAnnotations := []checkAnnotation{}
+	for _, annotation := range annotations {
+		counts[annotation.Level]++
+		a := checkAnnotation{
+			Path:            annotation.File,
+			StartLine:       max(annotation.Line, 1),
+			EndLine:         max(annotation.EndLine, annotation.Line, 1),
+			AnnotationLevel: checkLevel(annotation.Level),
+			Title:           annotation.Title,
+			Message:         annotation.Message,
+			RawDetails:      annotation.Job,
+		}
+		if annotation.Step != "" {
+			a.RawDetails = fmt.Sprintf("%s: %s", annotation.Job, annotation.Step)
+		}
+		if a.Path == "" {
+			a.Path = annotation.Workflow
+		}
+		// columns are only supported by the Checks API within a line
+		if a.StartLine == a.EndLine {
+			a.StartColumn = annotation.Column
+			a.EndColumn = annotation.EndColumn
+		}
+		checkAnnotations = append(checkAnnotations, a)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(checkOutput{
+		Title:       "act",
+		Summary:     countSummary(counts),
+		Annotations: checkAnnotations,
+	})
+}
+
+func checkLevel(level string) string {
+	switch level {
+	case "error":
+		return "failure"
+	case "warning":
+		return "warning"
+	default:
+		return "notice"
+	}
+}
@@ -0,0 +1,162 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/nektos/act/pkg/runner"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite is a testsuite of a JUnit XML report
+type JUnitTestSuite struct {
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Time       string           `xml:"time,attr"`
+	Timestamp  string           `xml:"timestamp,attr,omitempty"`
+	Properties *JUnitProperties `xml:"properties,omitempty"`
+	Cases      []JUnitTestCase  `xml:"testcase"`
+}
+
+// JUnitProperties are the properties of a testsuite
+type JUnitProperties struct {
+	Properties []JUnitProperty `xml:"property"`
+}
+
+// JUnitProperty is a property of a testsuite
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// JUnitTestCase is a testcase of a testsuite, it failed if it has a failure
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure is the failure of a testcase
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the jobs as JUnit XML, with a testsuite per job and matrix combination and a testcase per step
+func WriteJUnit(w io.Writer, records []runner.JobRecord) error {
+	suites := JUnitTestSuites{Name: "act"}
+	var total time.Duration
+	for _, record := range records {
+		suite := newJUnitTestSuite(record)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		total += record.FinishedAt.Sub(record.StartedAt)
+		suites.Suites = append(suites.Suites, suite)
+	}
+	suites.Time = JUnitTime(total)
+	return WriteJUnitTestSuites(w, suites)
+}
+
+// WriteJUnitTestSuites writes the test suites as an indented JUnit XML document
+func WriteJUnitTestSuites(w io.Writer, suites JUnitTestSuites) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newJUnitTestSuite(record runner.JobRecord) JUnitTestSuite {
+	suite := JUnitTestSuite{
+		Name:      fmt.Sprintf("%s/%s", record.WorkflowName, record.Name),
+		Time:      JUnitTime(record.FinishedAt.Sub(record.StartedAt)),
+		Timestamp: record.StartedAt.UTC().Format("2006-01-02T15:04:05"),
+	}
+	properties := []JUnitProperty{}
+	keys := make([]string, 0, len(record.Matrix))
+	for key := range record.Matrix {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		properties = append(properties, JUnitProperty{Name: "matrix." + key, Value: fmt.Sprint(record.Matrix[key])})
+	}
+	if record.Reused {
+		properties = append(properties, JUnitProperty{Name: "reused", Value: "true"})
+	}
+	if len(properties) > 0 {
+		suite.Properties = &JUnitProperties{Properties: properties}
+	}
+
+	className := fmt.Sprintf("%s.%s", record.Workflow, record.JobID)
+	failedStep := false
+	for _, step := range record.Steps {
+		testCase := JUnitTestCase{
+			Name:      step.Name,
+			ClassName: className,
+			Time:      JUnitTime(step.Duration),
+			SystemOut: step.Log,
+		}
+		switch step.Conclusion {
+		case "failure":
+			message := step.Error
+			if message == "" {
+				message = fmt.Sprintf("step %s", step.Outcome)
+			}
+			testCase.Failure = &JUnitFailure{Message: message, Text: step.Log}
+			failedStep = true
+		case "skipped":
+			testCase.Skipped = &struct{}{}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	// a job can fail outside of its steps, e.g. when its container does not start
+	if record.Result == "failure" && !failedStep {
+		suite.Cases = append(suite.Cases, JUnitTestCase{
+			Name:      record.Name,
+			ClassName: className,
+			Time:      suite.Time,
+			Failure:   &JUnitFailure{Message: fmt.Sprintf("job %s", record.Result), Text: record.Log},
+		})
+	}
+
+	for _, testCase := range suite.Cases {
+		suite.Tests++
+		if testCase.Failure != nil {
+			suite.Failures++
+		}
+		if testCase.Skipped != nil {
+			suite.Skipped++
+		}
+	}
+	return suite
+}
+
+// JUnitTime formats a duration as the seconds of the time attribute of JUnit XML
+func JUnitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
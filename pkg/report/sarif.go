@@ -0,0 +1,111 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId,omitempty"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// WriteSARIF writes the annotations as a SARIF log
+func WriteSARIF(w io.Writer, annotations []Annotation) error {
+	results := []sarifResult{}
+	for _, annotation := range annotations {
+		result := sarifResult{
+			RuleID:  annotation.Title,
+			Level:   sarifLevel(annotation.Level),
+			Message: sarifMessage{Text: annotation.Message},
+			Properties: map[string]string{
+				"workflow": annotation.Workflow,
+				"job":      annotation.Job,
+				"step":     annotation.Step,
+			},
+		}
+		if annotation.File != "" {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: annotation.File}}}
+			if annotation.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{
+					StartLine:   annotation.Line,
+					StartColumn: annotation.Column,
+					EndLine:     annotation.EndLine,
+					EndColumn:   annotation.EndColumn,
+				}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		results = append(results, result)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "act", InformationURI: "https://github.com/nektos/act"}},
+			Results: results,
+		}},
+	})
+}
+
+func sarifLevel(level string) string {
+	switch level {
+	case "error":
+		return "error"
+	case "warning":
+		return "warning"
+	default:
+		return "note"
+	}
+}
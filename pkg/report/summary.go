@@ -0,0 +1,145 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nektos/act/pkg/runner"
+	"github.com/russross/blackfriday/v2"
+)
+
+// HasSummaries returns whether a step of the jobs wrote a summary
+func HasSummaries(records []runner.JobRecord) bool {
+	for _, record := range records {
+		for _, step := range record.Steps {
+			if step.Summary != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WriteMarkdown writes the report of a run with the results and durations of the jobs and steps, the deployment environments
+// of the jobs and the summaries of the steps
+func WriteMarkdown(w io.Writer, records []runner.JobRecord) {
+	fmt.Fprint(w, "# Run report\n\n")
+	fmt.Fprint(w, "| Job | Result | Duration |\n| --- | --- | --- |\n")
+	for _, record := range records {
+		fmt.Fprintf(w, "| %s | %s | %s |\n", markdownCell(jobTitle(record)), resultLabel(record.Result), formatDuration(record.FinishedAt.Sub(record.StartedAt)))
+	}
+
+	for _, record := range records {
+		fmt.Fprintf(w, "\n## %s\n\n", jobTitle(record))
+		if record.Reused {
+			fmt.Fprint(w, "The result of a previous run was reused.\n")
+			continue
+		}
+		if record.EnvironmentURL != "" {
+			fmt.Fprintf(w, "Environment: [%s](%s)\n\n", record.Environment, record.EnvironmentURL)
+		} else if record.Environment != "" {
+			fmt.Fprintf(w, "Environment: %s\n\n", record.Environment)
+		}
+		if len(record.Steps) == 0 {
+			fmt.Fprint(w, "No steps ran.\n")
+			continue
+		}
+		fmt.Fprint(w, "| Step | Result | Duration |\n| --- | --- | --- |\n")
+		for _, step := range record.Steps {
+			name, _, _ := strings.Cut(step.Name, "\n")
+			fmt.Fprintf(w, "| %s | %s | %s |\n", markdownCell(name), resultLabel(step.Conclusion), formatDuration(step.Duration))
+		}
+		writeSummaries(w, record, "###")
+	}
+}
+
+// WriteSummaries writes the summaries of the steps grouped by job
+func WriteSummaries(w io.Writer, records []runner.JobRecord) {
+	for _, record := range records {
+		writeSummaries(w, record, "#")
+	}
+}
+
+func writeSummaries(w io.Writer, record runner.JobRecord, heading string) {
+	for _, step := range record.Steps {
+		if step.Summary == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(step.Name, "\n")
+		fmt.Fprintf(w, "\n%s %s: %s\n\n", heading, jobTitle(record), name)
+		fmt.Fprint(w, strings.TrimSuffix(step.Summary, "\n"), "\n")
+	}
+}
+
+// WriteHTML writes the report of WriteMarkdown as a self-contained HTML page. Like on GitHub, the raw HTML
+// of the summaries is dropped and unsafe links are not rendered, so a summary cannot run script in the report.
+func WriteHTML(w io.Writer, records []runner.JobRecord) error {
+	markdown := &bytes.Buffer{}
+	WriteMarkdown(markdown, records)
+	renderer := blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
+		Flags: blackfriday.CommonHTMLFlags | blackfriday.SkipHTML | blackfriday.Safelink,
+	})
+	body := blackfriday.Run(markdown.Bytes(), blackfriday.WithRenderer(renderer))
+	_, err := fmt.Fprintf(w, htmlTemplate, body)
+	return err
+}
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Run report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.5; max-width: 1012px; margin: 0 auto; padding: 32px; color: #1f2328; }
+h1, h2 { border-bottom: 1px solid #d1d9e0; padding-bottom: .3em; }
+table { border-collapse: collapse; margin: 16px 0; }
+th, td { border: 1px solid #d1d9e0; padding: 6px 13px; }
+tr:nth-child(2n) { background-color: #f6f8fa; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; background-color: #f6f8fa; border-radius: 6px; }
+code { padding: .2em .4em; }
+pre { padding: 16px; overflow: auto; }
+pre code { padding: 0; }
+</style>
+</head>
+<body>
+%s</body>
+</html>
+`
+
+func jobTitle(record runner.JobRecord) string {
+	return fmt.Sprintf("%s / %s", record.WorkflowName, record.Name)
+}
+
+func resultLabel(result string) string {
+	switch result {
+	case "success":
+		return "✅ success"
+	case "failure":
+		return "❌ failure"
+	case "skipped":
+		return "⏭️ skipped"
+	case "cancelled":
+		return "\U0001F6AB cancelled"
+	case "":
+		return "unknown"
+	default:
+		return result
+	}
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
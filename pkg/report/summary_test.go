@@ -0,0 +1,98 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/nektos/act/pkg/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderSummaries(t *testing.T) {
+	records := []runner.JobRecord{
+		{WorkflowName: "ci", Name: "build", Steps: []runner.StepRecord{{Name: "compile"}}},
+		{WorkflowName: "ci", Name: "test", Steps: []runner.StepRecord{{
+			Name:    "unit tests",
+			Summary: "## Results\n\nAll **42** tests passed, see [logs](https://example.com).\n\n| Suite | Passed |\n| --- | --- |\n| unit | 40 |\n| integration | 2 |\n\n- fast\n- slow\n  - flaky\n\nRun:\n\n```\ngo test ./...\n```\n",
+		}}},
+	}
+	assert.True(t, HasSummaries(records))
+	assert.False(t, HasSummaries(records[:1]))
+
+	buf := &bytes.Buffer{}
+	RenderSummaries(buf, records, false)
+	assert.Equal(t, `ci / test: unit tests
+
+Results
+
+All 42 tests passed, see logs (https://example.com).
+
+Suite        Passed  
+unit         40      
+integration  2       
+
+• fast
+• slow
+  • flaky
+
+Run:
+
+    go test ./...
+
+`, buf.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []runner.JobRecord{
+		{WorkflowName: "ci", Name: "build", Result: "success", StartedAt: started, FinishedAt: started.Add(90 * time.Second), Environment: "staging", EnvironmentURL: "https://staging.example.com", Steps: []runner.StepRecord{
+			{Name: "compile | link", Conclusion: "success", Duration: 80 * time.Second, Summary: "Built **1** binary"},
+			{Name: "publish", Conclusion: "skipped"},
+		}},
+		{WorkflowName: "ci", Name: "lint", Result: "success", Reused: true},
+	}
+
+	buf := &bytes.Buffer{}
+	WriteMarkdown(buf, records)
+	assert.Equal(t, `# Run report
+
+| Job | Result | Duration |
+| --- | --- | --- |
+| ci / build | ✅ success | 1m30s |
+| ci / lint | ✅ success | - |
+
+## ci / build
+
+Environment: [staging](https://staging.example.com)
+
+| Step | Result | Duration |
+| --- | --- | --- |
+| compile \| link | ✅ success | 1m20s |
+| publish | ⏭️ skipped | - |
+
+### ci / build: compile | link
+
+Built **1** binary
+
+## ci / lint
+
+The result of a previous run was reused.
+`, buf.String())
+}
+
+func TestWriteHTML(t *testing.T) {
+	records := []runner.JobRecord{
+		{WorkflowName: "ci", Name: "build", Result: "success", Steps: []runner.StepRecord{{
+			Name:    "compile",
+			Summary: "Built **1** binary\n\n<script>alert(1)</script>\n\n<img src=x onerror=alert(2)> [docs](javascript:alert(3))\n",
+		}}},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteHTML(buf, records))
+	assert.Contains(t, buf.String(), "Built <strong>1</strong> binary")
+	assert.NotContains(t, buf.String(), "<script>")
+	assert.NotContains(t, buf.String(), "onerror")
+	assert.NotContains(t, buf.String(), `href="javascript:`)
+}
@@ -0,0 +1,176 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nektos/act/pkg/runner"
+	"github.com/russross/blackfriday/v2"
+)
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiCode  = "\x1b[36m"
+	ansiLink  = "\x1b[4m"
+	ansiReset = "\x1b[0m"
+)
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// RenderSummaries renders the summaries of the steps grouped by job for a terminal
+func RenderSummaries(w io.Writer, records []runner.JobRecord, color bool) {
+	markdown := &bytes.Buffer{}
+	WriteSummaries(markdown, records)
+	renderer := &terminalRenderer{color: color}
+	_, _ = w.Write(blackfriday.Run(markdown.Bytes(), blackfriday.WithRenderer(renderer)))
+}
+
+// terminalRenderer renders markdown as plain text, with ANSI styles if color is enabled
+type terminalRenderer struct {
+	color bool
+}
+
+func (r *terminalRenderer) style(w io.Writer, code string) {
+	if r.color {
+		_, _ = io.WriteString(w, code)
+	}
+}
+
+//nolint:gocyclo
+func (r *terminalRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+	switch node.Type {
+	case blackfriday.Heading:
+		if entering {
+			r.style(w, ansiBold)
+		} else {
+			r.style(w, ansiReset)
+			fmt.Fprint(w, "\n\n")
+		}
+	case blackfriday.Paragraph:
+		if !entering {
+			fmt.Fprint(w, "\n")
+			if node.Parent == nil || node.Parent.Type != blackfriday.Item {
+				fmt.Fprint(w, "\n")
+			}
+		}
+	case blackfriday.List:
+		if !entering && (node.Parent == nil || node.Parent.Type != blackfriday.Item) {
+			fmt.Fprint(w, "\n")
+		}
+	case blackfriday.Item:
+		if entering {
+			fmt.Fprint(w, strings.Repeat("  ", listDepth(node)-1))
+			if node.ListFlags&blackfriday.ListTypeOrdered != 0 {
+				fmt.Fprintf(w, "%d. ", itemIndex(node))
+			} else {
+				fmt.Fprint(w, "• ")
+			}
+		}
+	case blackfriday.BlockQuote:
+		if entering {
+			fmt.Fprint(w, "│ ")
+		}
+	case blackfriday.CodeBlock:
+		r.style(w, ansiCode)
+		for _, line := range strings.Split(strings.TrimSuffix(string(node.Literal), "\n"), "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+		r.style(w, ansiReset)
+		fmt.Fprint(w, "\n")
+	case blackfriday.Code:
+		r.style(w, ansiCode)
+		_, _ = w.Write(node.Literal)
+		r.style(w, ansiReset)
+	case blackfriday.Emph, blackfriday.Strong:
+		if entering {
+			r.style(w, ansiBold)
+		} else {
+			r.style(w, ansiReset)
+		}
+	case blackfriday.Link:
+		if entering {
+			r.style(w, ansiLink)
+		} else {
+			r.style(w, ansiReset)
+			fmt.Fprintf(w, " (%s)", node.LinkData.Destination)
+		}
+	case blackfriday.Image:
+		if entering {
+			fmt.Fprint(w, "[image: ")
+		} else {
+			fmt.Fprint(w, "]")
+		}
+	case blackfriday.Text:
+		_, _ = w.Write(node.Literal)
+	case blackfriday.Softbreak:
+		fmt.Fprint(w, " ")
+	case blackfriday.Hardbreak:
+		fmt.Fprint(w, "\n")
+	case blackfriday.HorizontalRule:
+		fmt.Fprint(w, strings.Repeat("─", 40), "\n\n")
+	case blackfriday.HTMLSpan:
+		_, _ = w.Write(htmlTag.ReplaceAll(node.Literal, nil))
+	case blackfriday.HTMLBlock:
+		if text := strings.Join(strings.Fields(htmlTag.ReplaceAllString(string(node.Literal), " ")), " "); text != "" {
+			fmt.Fprint(w, text, "\n\n")
+		}
+	case blackfriday.Table:
+		r.renderTable(w, node)
+		return blackfriday.SkipChildren
+	}
+	return blackfriday.GoToNext
+}
+
+// renderTable aligns the cells of a table in columns
+func (r *terminalRenderer) renderTable(w io.Writer, table *blackfriday.Node) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	plain := &terminalRenderer{}
+	table.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		switch node.Type {
+		case blackfriday.TableRow:
+			if !entering {
+				fmt.Fprint(tw, "\n")
+			}
+		case blackfriday.TableCell:
+			if entering {
+				cell := &bytes.Buffer{}
+				for child := node.FirstChild; child != nil; child = child.Next {
+					child.Walk(func(n *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+						return plain.RenderNode(cell, n, entering)
+					})
+				}
+				fmt.Fprintf(tw, "%s\t", cell.String())
+				return blackfriday.SkipChildren
+			}
+		}
+		return blackfriday.GoToNext
+	})
+	_ = tw.Flush()
+	fmt.Fprint(w, "\n")
+}
+
+func (r *terminalRenderer) RenderHeader(_ io.Writer, _ *blackfriday.Node) {}
+
+func (r *terminalRenderer) RenderFooter(_ io.Writer, _ *blackfriday.Node) {}
+
+func listDepth(item *blackfriday.Node) int {
+	depth := 0
+	for node := item; node != nil; node = node.Parent {
+		if node.Type == blackfriday.List {
+			depth++
+		}
+	}
+	return depth
+}
+
+func itemIndex(item *blackfriday.Node) int {
+	index := 1
+	for node := item.Prev; node != nil; node = node.Prev {
+		index++
+	}
+	return index
+}
@@ -0,0 +1,138 @@
+package runhistory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+// Run is a recorded run of act
+type Run struct {
+	ID            uint64             `json:"id" boltholdKey:"ID"`
+	StartedAt     int64              `json:"startedAt" boltholdIndex:"StartedAt"`
+	FinishedAt    int64              `json:"finishedAt"`
+	RerunOf       uint64             `json:"rerunOf,omitempty"` // the run this run re-executed
+	EventName     string             `json:"eventName"`
+	EventJSON     string             `json:"eventJSON,omitempty"` // the event payload, empty if it was built from the inputs
+	Inputs        map[string]string  `json:"inputs,omitempty"`
+	Workdir       string             `json:"workdir"`
+	WorkflowsPath string             `json:"workflowsPath"`
+	Stages        [][]PlannedJob     `json:"stages"`
+	Conclusion    string             `json:"conclusion"`
+	Error         string             `json:"error,omitempty"`
+	Jobs          []runner.JobRecord `json:"jobs"`
+}
+
+// PlannedJob is a job of the plan of a run
+type PlannedJob struct {
+	Workflow string   `json:"workflow"` // file of the workflow
+	JobID    string   `json:"jobID"`
+	Needs    []string `json:"needs,omitempty"`
+}
+
+// NewRun starts the record of a run of the plan
+func NewRun(plan *model.Plan, config *runner.Config, workflowsPath string) *Run {
+	run := &Run{
+		StartedAt:     time.Now().Unix(),
+		EventName:     config.EventName,
+		EventJSON:     config.EventJSON,
+		Inputs:        config.Inputs,
+		Workdir:       config.Workdir,
+		WorkflowsPath: workflowsPath,
+		Jobs:          []runner.JobRecord{},
+	}
+	for _, stage := range plan.Stages {
+		jobs := []PlannedJob{}
+		for _, r := range stage.Runs {
+			jobs = append(jobs, PlannedJob{Workflow: r.Workflow.File, JobID: r.JobID, Needs: r.Job().Needs()})
+		}
+		run.Stages = append(run.Stages, jobs)
+	}
+	return run
+}
+
+// Results returns the result of each job of the run by runner.JobKey, the results of the combinations of a matrix are merged
+func (r *Run) Results() map[string]string {
+	results := map[string]string{}
+	for _, job := range r.Jobs {
+		key := runner.JobKey(job.Workflow, job.JobID)
+		results[key] = runner.MergeJobResults(results[key], job.Result)
+	}
+	return results
+}
+
+// ReusedJobs returns the jobs of the run which do not need to run again to re-run its failed jobs:
+// the successful or skipped jobs which do not need a failed one, directly or transitively
+func (r *Run) ReusedJobs() map[string]runner.ReusedJob {
+	results := r.Results()
+	rerun := map[string]bool{}
+	// the stages are ordered, the needs of a job are planned in an earlier stage
+	for _, stage := range r.Stages {
+		for _, job := range stage {
+			key := runner.JobKey(job.Workflow, job.JobID)
+			rerun[key] = results[key] != "success" && results[key] != "skipped"
+			for _, need := range job.Needs {
+				rerun[key] = rerun[key] || rerun[runner.JobKey(job.Workflow, need)]
+			}
+		}
+	}
+
+	reused := map[string]runner.ReusedJob{}
+	for _, job := range r.Jobs {
+		key := runner.JobKey(job.Workflow, job.JobID)
+		if rerun[key] {
+			continue
+		}
+		reused[key] = runner.ReusedJob{Result: results[key], Outputs: job.Outputs}
+	}
+	return reused
+}
+
+// PlannedJobs returns the jobs of the plan of the run by the file of their workflow
+func (r *Run) PlannedJobs() map[string][]string {
+	jobs := map[string][]string{}
+	for _, stage := range r.Stages {
+		for _, job := range stage {
+			jobs[job.Workflow] = append(jobs[job.Workflow], job.JobID)
+		}
+	}
+	return jobs
+}
+
+// Recorder collects the records of the jobs of a run while it executes
+type Recorder struct {
+	mu  sync.Mutex
+	run *Run
+}
+
+// NewRecorder returns a recorder of the jobs of the run
+func NewRecorder(run *Run) *Recorder {
+	return &Recorder{run: run}
+}
+
+// RecordJob adds the record of a completed job to the run, it is a runner.JobRecorder
+func (r *Recorder) RecordJob(record runner.JobRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.Jobs = append(r.run.Jobs, record)
+}
+
+// Finish completes the record of the run with the error of its execution
+func (r *Recorder) Finish(err error) *Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.FinishedAt = time.Now().Unix()
+	r.run.Conclusion = "success"
+	for _, result := range r.run.Results() {
+		r.run.Conclusion = runner.MergeJobResults(r.run.Conclusion, result)
+	}
+	if err != nil {
+		r.run.Error = err.Error()
+		if r.run.Conclusion == "success" {
+			r.run.Conclusion = "failure"
+		}
+	}
+	return r.run
+}
@@ -0,0 +1,110 @@
+package runhistory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/timshannon/bolthold"
+	"go.etcd.io/bbolt"
+)
+
+// keepRuns is the number of runs kept in the history, older runs are removed
+const keepRuns = 100
+
+// ErrRunNotFound is returned for runs which are not in the history
+var ErrRunNotFound = errors.New("run not found")
+
+// Store keeps the history of the runs in a bolt database
+type Store struct {
+	dir string
+}
+
+// Open returns the store of the history in the directory, which is created if needed
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+// openDB opens the database for a single operation, so that concurrent invocations of act do not block each other
+func (s *Store) openDB() (*bolthold.Store, error) {
+	return bolthold.Open(filepath.Join(s.dir, "bolt.db"), 0o644, &bolthold.Options{
+		Encoder: json.Marshal,
+		Decoder: json.Unmarshal,
+		Options: &bbolt.Options{
+			Timeout:      5 * time.Second,
+			NoGrowSync:   bbolt.DefaultOptions.NoGrowSync,
+			FreelistType: bbolt.DefaultOptions.FreelistType,
+		},
+	})
+}
+
+// Save adds a run to the history and assigns its ID, the oldest runs are removed
+func (s *Store) Save(run *Run) error {
+	db, err := s.openDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.Insert(bolthold.NextSequence(), run); err != nil {
+		return fmt.Errorf("insert run: %w", err)
+	}
+	// write back id to db
+	if err := db.Update(run.ID, run); err != nil {
+		return fmt.Errorf("write back id to db: %w", err)
+	}
+
+	var old []*Run
+	if err := db.Find(&old, (&bolthold.Query{}).SortBy("StartedAt", "ID").Reverse().Skip(keepRuns)); err != nil {
+		return fmt.Errorf("find old runs: %w", err)
+	}
+	for _, r := range old {
+		if err := db.Delete(r.ID, r); err != nil {
+			return fmt.Errorf("delete run %d: %w", r.ID, err)
+		}
+	}
+	return nil
+}
+
+// List returns the most recent runs of the history, the most recent first
+func (s *Store) List(limit int) ([]*Run, error) {
+	db, err := s.openDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	runs := []*Run{}
+	query := (&bolthold.Query{}).SortBy("StartedAt", "ID").Reverse()
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := db.Find(&runs, query); err != nil {
+		return nil, fmt.Errorf("find runs: %w", err)
+	}
+	return runs, nil
+}
+
+// Get returns a run of the history
+func (s *Store) Get(id uint64) (*Run, error) {
+	db, err := s.openDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	run := &Run{}
+	if err := db.Get(id, run); err != nil {
+		if errors.Is(err, bolthold.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %d", ErrRunNotFound, id)
+		}
+		return nil, err
+	}
+	return run, nil
+}
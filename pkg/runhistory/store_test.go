@@ -0,0 +1,90 @@
+package runhistory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nektos/act/pkg/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	store, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	first := &Run{StartedAt: 100, EventName: "push", Jobs: []runner.JobRecord{{Workflow: "ci.yml", JobID: "build", Result: "success", Log: "built\n"}}}
+	second := &Run{StartedAt: 200, EventName: "pull_request"}
+	require.NoError(t, store.Save(first))
+	require.NoError(t, store.Save(second))
+	assert.NotZero(t, first.ID)
+	assert.NotEqual(t, first.ID, second.ID)
+
+	runs, err := store.List(0)
+	require.NoError(t, err)
+	assert.Equal(t, []*Run{second, first}, runs)
+
+	runs, err = store.List(1)
+	require.NoError(t, err)
+	assert.Equal(t, []*Run{second}, runs)
+
+	run, err := store.Get(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first, run)
+
+	_, err = store.Get(42)
+	assert.True(t, errors.Is(err, ErrRunNotFound))
+}
+
+func TestRunReusedJobs(t *testing.T) {
+	run := &Run{
+		Stages: [][]PlannedJob{
+			{{Workflow: "ci.yml", JobID: "build"}, {Workflow: "ci.yml", JobID: "lint"}, {Workflow: "ci.yml", JobID: "docs"}},
+			{{Workflow: "ci.yml", JobID: "test", Needs: []string{"build"}}, {Workflow: "ci.yml", JobID: "fmt", Needs: []string{"lint"}}},
+			{{Workflow: "ci.yml", JobID: "deploy", Needs: []string{"test"}}},
+		},
+		Jobs: []runner.JobRecord{
+			{Workflow: "ci.yml", JobID: "build", Result: "success", Outputs: map[string]string{"version": "1.0.0"}},
+			{Workflow: "ci.yml", JobID: "lint", Result: "success"},
+			{Workflow: "ci.yml", JobID: "lint", Result: "failure", Matrix: map[string]interface{}{"go": "1.22"}},
+			{Workflow: "ci.yml", JobID: "docs", Result: "skipped"},
+			{Workflow: "ci.yml", JobID: "test", Result: "success"},
+			{Workflow: "ci.yml", JobID: "fmt", Result: "skipped"},
+			{Workflow: "ci.yml", JobID: "deploy", Result: "cancelled"},
+		},
+	}
+
+	assert.Equal(t, map[string]runner.ReusedJob{
+		"ci.yml/build": {Result: "success", Outputs: map[string]string{"version": "1.0.0"}},
+		"ci.yml/docs":  {Result: "skipped"},
+		"ci.yml/test":  {Result: "success"},
+	}, run.ReusedJobs())
+
+	assert.Equal(t, "failure", NewRecorder(run).Finish(nil).Conclusion)
+}
+
+func TestRecorderFinish(t *testing.T) {
+	tables := []struct {
+		name       string
+		results    []string
+		err        error
+		conclusion string
+	}{
+		{"success", []string{"success", "skipped"}, nil, "success"},
+		{"cancelled", []string{"success", "cancelled"}, errors.New("Job 'b' was cancelled"), "cancelled"},
+		{"failure", []string{"cancelled", "failure"}, errors.New("Job 'b' failed"), "failure"},
+		{"error", []string{}, errors.New("invalid inputs"), "failure"},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			recorder := NewRecorder(&Run{})
+			for i, result := range table.results {
+				recorder.RecordJob(runner.JobRecord{Workflow: "ci.yml", JobID: string(rune('a' + i)), Result: result})
+			}
+			run := recorder.Finish(table.err)
+			assert.Equal(t, table.conclusion, run.Conclusion)
+			assert.NotZero(t, run.FinishedAt)
+		})
+	}
+}
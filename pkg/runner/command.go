@@ -39,6 +39,7 @@ func (rc *RunContext) commandHandler(ctx context.Context) common.LineHandler {
 	return func(line string) bool {
 		command, kvPairs, arg, ok := tryParseRawActionCommand(line)
 		if !ok {
+			rc.matchProblems(ctx, line)
 			return true
 		}
 
@@ -74,8 +75,21 @@ func (rc *RunContext) commandHandler(ctx context.Context) common.LineHandler {
 		case "save-state":
 			defCommandLogger.Infof("  \U0001f4be  %s", line)
 			rc.saveState(ctx, kvPairs, arg)
+		case "group":
+			defCommandLogger.Infof("  \U000025BC  %s", arg)
+		case "endgroup":
+			// the group formatter of the job logger names the group which ended
+			defCommandLogger.Infof("  \U000025B2  %s", line)
+		case "notice":
+			defCommandLogger.Infof("  \U0001F4DD  %s", line)
 		case "add-matcher":
-			defCommandLogger.Infof("  \U00002753 add-matcher %s", arg)
+			defCommandLogger.Infof("  \U00002699  add-matcher %s", arg)
+			if err := rc.addProblemMatchers(ctx, arg); err != nil {
+				defCommandLogger.Warnf("  \U0001F6A7  unable to add the problem matchers of '%s': %v", arg, err)
+			}
+		case "remove-matcher":
+			defCommandLogger.Infof("  \U00002699  remove-matcher %s", kvPairs["owner"])
+			rc.removeProblemMatcher(kvPairs["owner"])
 		default:
 			defCommandLogger.Infof("  \U00002753  %s", line)
 		}
@@ -120,6 +134,7 @@ func (rc *RunContext) setOutput(ctx context.Context, kvPairs map[string]string,
 
 	logger.WithFields(logrus.Fields{"command": "set-output", "name": outputName, "arg": arg}).Infof("  \U00002699  ::set-output:: %s=%s", outputName, arg)
 	result.Outputs[outputName] = arg
+	rc.emitOutput(ctx, stepID, outputName, arg)
 }
 func (rc *RunContext) addPath(ctx context.Context, arg string) {
 	common.Logger(ctx).WithFields(logrus.Fields{"command": "add-path", "arg": arg}).Infof("  \U00002699  ::add-path:: %s", arg)
@@ -0,0 +1,507 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+// concurrencyPollInterval is the interval in which runs waiting for or holding a concurrency group check its state
+var concurrencyPollInterval = time.Second
+
+var errConcurrencyCancelled = errors.New("cancelled by a newer run of the concurrency group")
+
+var concurrencyTokenCounter atomic.Uint64
+
+// concurrencyState is the state of a concurrency group shared by all act processes of the machine.
+// Like on GitHub, a group has at most one running and one pending run, a newer run replaces the pending one.
+type concurrencyState struct {
+	Holder           string    `json:"holder"`
+	Heartbeat        time.Time `json:"heartbeat"`
+	Pending          string    `json:"pending"`
+	PendingHeartbeat time.Time `json:"pendingHeartbeat"`
+	CancelRequested  bool      `json:"cancelRequested"`
+}
+
+// a holder or pending run is considered dead if it misses several heartbeats, e.g. because act was killed
+func isStaleHeartbeat(heartbeat time.Time) bool {
+	return time.Since(heartbeat) > 10*concurrencyPollInterval
+}
+
+// concurrencyRegistry is the file with the states of the concurrency groups. The file stays open for the whole run,
+// every update locks it, so that the act processes of the machine see each other's changes.
+type concurrencyRegistry struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func openConcurrencyRegistry(dir string) (*concurrencyRegistry, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, "concurrency.json")
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the concurrency registry '%s': %w", path, err)
+	}
+	return &concurrencyRegistry{file: file}, nil
+}
+
+func (r *concurrencyRegistry) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+func (r *concurrencyRegistry) update(group string, fn func(state *concurrencyState)) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := lockFile(r.file); err != nil {
+		return fmt.Errorf("unable to lock the concurrency registry '%s': %w", r.file.Name(), err)
+	}
+	defer func() {
+		err = errors.Join(err, unlockFile(r.file))
+	}()
+
+	data, err := io.ReadAll(io.NewSectionReader(r.file, 0, math.MaxInt64))
+	if err != nil {
+		return err
+	}
+	states := map[string]*concurrencyState{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &states); err != nil {
+			return fmt.Errorf("invalid concurrency registry '%s': %w", r.file.Name(), err)
+		}
+	}
+	state, ok := states[group]
+	if !ok {
+		state = &concurrencyState{}
+	}
+	fn(state)
+	if state.Holder == "" && state.Pending == "" {
+		delete(states, group)
+	} else {
+		states[group] = state
+	}
+
+	if data, err = json.Marshal(states); err != nil {
+		return err
+	}
+	if err := r.file.Truncate(0); err != nil {
+		return err
+	}
+	_, err = r.file.WriteAt(data, 0)
+	return err
+}
+
+type concurrencyRegistriesContextKey struct{}
+
+// concurrencyRegistries are the registries opened by a run, by the directory of the registry
+type concurrencyRegistries struct {
+	mu         sync.Mutex
+	registries map[string]*concurrencyRegistry
+}
+
+// withConcurrencyRegistries keeps the registries opened by the run open until it completes, a nested run like the one
+// of a reusable workflow uses the registries of its caller
+func withConcurrencyRegistries(ctx context.Context) (context.Context, func()) {
+	if _, ok := ctx.Value(concurrencyRegistriesContextKey{}).(*concurrencyRegistries); ok {
+		return ctx, func() {}
+	}
+	registries := &concurrencyRegistries{registries: map[string]*concurrencyRegistry{}}
+	return context.WithValue(ctx, concurrencyRegistriesContextKey{}, registries), func() {
+		registries.mu.Lock()
+		defer registries.mu.Unlock()
+		for dir, registry := range registries.registries {
+			if err := registry.close(); err != nil {
+				common.Logger(ctx).Warnf("Unable to close the concurrency registry in '%s': %v", dir, err)
+			}
+		}
+		registries.registries = map[string]*concurrencyRegistry{}
+	}
+}
+
+// useConcurrencyRegistry calls fn with the registry in the directory, which is opened once per run
+func useConcurrencyRegistry(ctx context.Context, dir string, fn func(registry *concurrencyRegistry) error) error {
+	registries, ok := ctx.Value(concurrencyRegistriesContextKey{}).(*concurrencyRegistries)
+	if !ok {
+		registry, err := openConcurrencyRegistry(dir)
+		if err != nil {
+			return err
+		}
+		defer registry.close()
+		return fn(registry)
+	}
+
+	registries.mu.Lock()
+	registry, ok := registries.registries[dir]
+	if !ok {
+		var err error
+		if registry, err = openConcurrencyRegistry(dir); err != nil {
+			registries.mu.Unlock()
+			return err
+		}
+		registries.registries[dir] = registry
+	}
+	registries.mu.Unlock()
+	return fn(registry)
+}
+
+type heldConcurrencyGroupsContextKey struct{}
+
+// withHeldConcurrencyGroup marks a group as held by the workflow of the jobs run with the context
+func withHeldConcurrencyGroup(ctx context.Context, group string) context.Context {
+	return context.WithValue(ctx, heldConcurrencyGroupsContextKey{}, group)
+}
+
+// isHeldConcurrencyGroup reports whether the workflow of a job already holds the group
+func isHeldConcurrencyGroup(ctx context.Context, group string) bool {
+	held, ok := ctx.Value(heldConcurrencyGroupsContextKey{}).(string)
+	return ok && held == group
+}
+
+// acquire waits until the run holds the concurrency group. It fails with errConcurrencyCancelled if a newer run
+// replaced it while pending, and with the cause of the job cancellation context if the jobs are cancelled while pending.
+// onCancel is called when a newer run with cancel-in-progress asks the holder to stop.
+func (r *concurrencyRegistry) acquire(ctx context.Context, group string, cancelInProgress bool, onCancel func()) (func(), error) {
+	logger := common.Logger(ctx)
+	token := fmt.Sprintf("%d-%d-%d", os.Getpid(), time.Now().UnixNano(), concurrencyTokenCounter.Add(1))
+	var jobCancelled <-chan struct{}
+	if jobCancelCtx := common.JobCancelContext(ctx); jobCancelCtx != nil {
+		jobCancelled = jobCancelCtx.Done()
+	}
+	leave := func(cause error) (func(), error) {
+		err := r.update(group, func(state *concurrencyState) {
+			if state.Pending == token {
+				state.Pending = ""
+			}
+		})
+		return nil, errors.Join(cause, err)
+	}
+
+	take := func(state *concurrencyState) bool {
+		if state.Pending != "" && state.Pending != token && isStaleHeartbeat(state.PendingHeartbeat) {
+			state.Pending = ""
+		}
+		if state.Holder != "" && !isStaleHeartbeat(state.Heartbeat) {
+			return false
+		}
+		state.Holder = token
+		state.Heartbeat = time.Now()
+		state.CancelRequested = false
+		if state.Pending == token {
+			state.Pending = ""
+		}
+		return true
+	}
+
+	acquired := false
+	if err := r.update(group, func(state *concurrencyState) {
+		if acquired = take(state); acquired {
+			return
+		}
+		state.Pending = token
+		state.PendingHeartbeat = time.Now()
+		if cancelInProgress {
+			state.CancelRequested = true
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	if !acquired {
+		logger.Infof("\u23F3  Waiting for the concurrency group '%s'", group)
+		ticker := time.NewTicker(concurrencyPollInterval)
+		defer ticker.Stop()
+		for !acquired {
+			select {
+			case <-ctx.Done():
+				return leave(ctx.Err())
+			case <-jobCancelled:
+				return leave(context.Cause(common.JobCancelContext(ctx)))
+			case <-ticker.C:
+			}
+
+			superseded := false
+			if err := r.update(group, func(state *concurrencyState) {
+				if state.Pending != token {
+					superseded = true
+					return
+				}
+				if acquired = take(state); !acquired {
+					state.PendingHeartbeat = time.Now()
+				}
+			}); err != nil {
+				return nil, err
+			}
+			if superseded {
+				return nil, errConcurrencyCancelled
+			}
+		}
+	}
+	logger.Debugf("Acquired the concurrency group '%s'", group)
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(context.Background())
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		ticker := time.NewTicker(concurrencyPollInterval)
+		defer ticker.Stop()
+		cancelled := false
+		for {
+			select {
+			case <-heartbeatCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			cancelRequested := false
+			if err := r.update(group, func(state *concurrencyState) {
+				if state.Holder == token {
+					state.Heartbeat = time.Now()
+					cancelRequested = state.CancelRequested
+				}
+			}); err != nil {
+				logger.Warnf("Unable to update the concurrency group '%s': %v", group, err)
+			}
+			if cancelRequested && !cancelled {
+				cancelled = true
+				logger.Infof("Cancelling the run, a newer run of the concurrency group '%s' is in progress", group)
+				onCancel()
+			}
+		}
+	}()
+
+	return func() {
+		stopHeartbeat()
+		<-heartbeatDone
+		if err := r.update(group, func(state *concurrencyState) {
+			if state.Holder == token {
+				state.Holder = ""
+				state.CancelRequested = false
+			}
+		}); err != nil {
+			logger.Warnf("Unable to release the concurrency group '%s': %v", group, err)
+		}
+	}, nil
+}
+
+// evaluateConcurrency returns the evaluated group and cancel-in-progress of a concurrency configuration
+func evaluateConcurrency(ctx context.Context, exprEval ExpressionEvaluator, concurrency *model.Concurrency) (string, bool, error) {
+	group := exprEval.Interpolate(ctx, concurrency.Group)
+	if concurrency.CancelInProgress == "" {
+		return group, false, nil
+	}
+	cancelInProgress, err := EvalBool(ctx, exprEval, concurrency.CancelInProgress, exprparser.DefaultStatusCheckNone)
+	if err != nil {
+		return "", false, fmt.Errorf("  \u274C  Error in cancel-in-progress-expression: \"cancel-in-progress: %s\" (%s)", concurrency.CancelInProgress, err)
+	}
+	return group, cancelInProgress, nil
+}
+
+// isJobCancelled reports whether act cancelled the job cancellation context, because another job of the matrix failed
+// or a newer run of the concurrency group replaced it, or the run was cancelled by Ctrl-C
+func isJobCancelled(cancelCtx context.Context) bool {
+	if cancelCtx == nil {
+		return false
+	}
+	cause := context.Cause(cancelCtx)
+	return errors.Is(cause, errMatrixFailFast) || errors.Is(cause, errConcurrencyCancelled) || errors.Is(cause, common.ErrRunCancelled)
+}
+
+// useJobConcurrency runs the job once it holds its concurrency group
+func (rc *RunContext) useJobConcurrency(executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		concurrency := rc.Run.Job().Concurrency()
+		if concurrency == nil || common.Dryrun(ctx) {
+			return executor(ctx)
+		}
+
+		group, cancelInProgress, err := evaluateConcurrency(ctx, rc.ExprEval, concurrency)
+		if err != nil {
+			return err
+		}
+		if group == "" {
+			return executor(ctx)
+		}
+
+		if isHeldConcurrencyGroup(ctx, group) {
+			common.Logger(ctx).Debugf("The workflow holds the concurrency group '%s' of the job", group)
+			return executor(ctx)
+		}
+
+		parent := common.JobCancelContext(ctx)
+		if parent == nil {
+			parent = ctx
+		}
+		cancelCtx, cancel := context.WithCancelCause(parent)
+		defer cancel(nil)
+
+		return useConcurrencyRegistry(ctx, rc.ActionCacheDir(), func(registry *concurrencyRegistry) error {
+			release, err := registry.acquire(ctx, group, cancelInProgress, func() {
+				cancel(errConcurrencyCancelled)
+			})
+			if errors.Is(err, errConcurrencyCancelled) {
+				rc.result(MergeJobResults(rc.Run.Job().Result, "cancelled"))
+				common.Logger(ctx).WithField("jobResult", "cancelled").Infof("\U0001F3C1  Job cancelled, a newer run of the concurrency group '%s' is pending", group)
+				return nil
+			} else if err != nil && isJobCancelled(common.JobCancelContext(ctx)) {
+				rc.result(MergeJobResults(rc.Run.Job().Result, "cancelled"))
+				common.Logger(ctx).WithField("jobResult", "cancelled").Infof("\U0001F3C1  Job cancelled while waiting for the concurrency group '%s'", group)
+				return nil
+			} else if err != nil {
+				return err
+			}
+			defer release()
+
+			return executor(common.WithJobCancelContext(ctx, cancelCtx))
+		})
+	}
+}
+
+// useWorkflowConcurrency runs the workflows of the plan with a concurrency group once they hold it. The workflows
+// sharing a group run one after another, the other workflows run alongside them. The jobs of a workflow use its
+// cancellation context, which is cancelled if a newer run of the group replaces it.
+func (runner *runnerImpl) useWorkflowConcurrency(plan *model.Plan, cancelCtxs map[*model.Workflow]context.Context, newExecutor func(plan *model.Plan) common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		ctx, closeRegistries := withConcurrencyRegistries(ctx)
+		defer closeRegistries()
+
+		if common.Dryrun(ctx) {
+			return newExecutor(plan)(ctx)
+		}
+
+		type workflowGroup struct {
+			workflow         *model.Workflow
+			cancelInProgress bool
+			cacheDir         string
+			cancel           context.CancelCauseFunc
+		}
+		// the workflows of every group, in the order of the plan
+		groups := []string{}
+		workflows := map[string][]*workflowGroup{}
+		grouped := map[*model.Workflow]bool{}
+		seen := map[*model.Workflow]bool{}
+		for _, stage := range plan.Stages {
+			for _, run := range stage.Runs {
+				if seen[run.Workflow] {
+					continue
+				}
+				seen[run.Workflow] = true
+				concurrency := run.Workflow.Concurrency()
+				if concurrency == nil {
+					continue
+				}
+				rc := runner.newRunContext(ctx, run, nil)
+				group, cancelInProgress, err := evaluateConcurrency(ctx, rc.ExprEval, concurrency)
+				if err != nil {
+					return err
+				}
+				if group == "" {
+					continue
+				}
+				if _, ok := workflows[group]; !ok {
+					groups = append(groups, group)
+				}
+				workflows[group] = append(workflows[group], &workflowGroup{workflow: run.Workflow, cancelInProgress: cancelInProgress, cacheDir: rc.ActionCacheDir()})
+				grouped[run.Workflow] = true
+			}
+		}
+		if len(groups) == 0 {
+			return newExecutor(plan)(ctx)
+		}
+
+		parent := common.JobCancelContext(ctx)
+		if parent == nil {
+			parent = ctx
+		}
+		for _, group := range groups {
+			for _, w := range workflows[group] {
+				cancelCtx, cancel := context.WithCancelCause(parent)
+				defer cancel(nil)
+				cancelCtxs[w.workflow] = cancelCtx
+				w.cancel = cancel
+			}
+		}
+
+		executors := []common.Executor{}
+		if rest := filterPlan(plan, func(run *model.Run) bool { return !grouped[run.Workflow] }); rest != nil {
+			executors = append(executors, newExecutor(rest))
+		}
+		for _, group := range groups {
+			executors = append(executors, func(ctx context.Context) error {
+				errs := []error{}
+				for _, w := range workflows[group] {
+					workflowPlan := filterPlan(plan, func(run *model.Run) bool { return run.Workflow == w.workflow })
+					errs = append(errs, useConcurrencyRegistry(ctx, w.cacheDir, func(registry *concurrencyRegistry) error {
+						release, err := registry.acquire(ctx, group, w.cancelInProgress, func() {
+							w.cancel(errConcurrencyCancelled)
+						})
+						if errors.Is(err, errConcurrencyCancelled) {
+							// the jobs of the workflow are reported as cancelled
+							common.Logger(ctx).Infof("Cancelling workflow '%s', a newer run of the concurrency group '%s' is pending", w.workflow.Name, group)
+							w.cancel(errConcurrencyCancelled)
+							return newExecutor(workflowPlan)(ctx)
+						} else if err != nil && isJobCancelled(cancelCtxs[w.workflow]) {
+							// the cancellation context of the workflow is cancelled as well, its jobs are reported as cancelled
+							common.Logger(ctx).Infof("Cancelling workflow '%s' while waiting for the concurrency group '%s'", w.workflow.Name, group)
+							return newExecutor(workflowPlan)(ctx)
+						} else if err != nil {
+							return err
+						}
+						defer release()
+						return newExecutor(workflowPlan)(withHeldConcurrencyGroup(ctx, group))
+					}))
+				}
+				return errors.Join(errs...)
+			})
+		}
+		return common.NewParallelExecutor(len(executors), executors...)(ctx)
+	}
+}
+
+// filterPlan returns the plan of the runs matching the filter, nil if no run matches
+func filterPlan(plan *model.Plan, filter func(run *model.Run) bool) *model.Plan {
+	filtered := &model.Plan{}
+	for _, stage := range plan.Stages {
+		runs := []*model.Run{}
+		for _, run := range stage.Runs {
+			if filter(run) {
+				runs = append(runs, run)
+			}
+		}
+		if len(runs) > 0 {
+			filtered.Stages = append(filtered.Stages, &model.Stage{Runs: runs})
+		}
+	}
+	if len(filtered.Stages) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// MergeJobResults combines the results of the jobs of a matrix, which share a single result, or of the jobs of a run:
+// a failure takes precedence over a cancellation, which takes precedence over a success
+func MergeJobResults(existing string, result string) string {
+	for _, r := range []string{"failure", "cancelled", "success"} {
+		if existing == r || result == r {
+			return r
+		}
+	}
+	if existing != "" {
+		return existing
+	}
+	return result
+}
@@ -0,0 +1,216 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setConcurrencyPollInterval(t *testing.T, interval time.Duration) {
+	old := concurrencyPollInterval
+	concurrencyPollInterval = interval
+	t.Cleanup(func() {
+		concurrencyPollInterval = old
+	})
+}
+
+func openTestConcurrencyRegistry(t *testing.T) *concurrencyRegistry {
+	registry, err := openConcurrencyRegistry(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, registry.close())
+	})
+	return registry
+}
+
+func TestConcurrencyRegistry(t *testing.T) {
+	setConcurrencyPollInterval(t, 10*time.Millisecond)
+	ctx := context.Background()
+	noCancel := func() {}
+
+	t.Run("serialize", func(t *testing.T) {
+		registry := openTestConcurrencyRegistry(t)
+		release, err := registry.acquire(ctx, "group", false, noCancel)
+		require.NoError(t, err)
+
+		acquired := make(chan struct{})
+		go func() {
+			release, err := registry.acquire(ctx, "group", false, noCancel)
+			assert.NoError(t, err)
+			release()
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("the group was acquired twice")
+		case <-time.After(100 * time.Millisecond):
+		}
+		release()
+		select {
+		case <-acquired:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the group was not acquired after its release")
+		}
+	})
+
+	t.Run("other-groups", func(t *testing.T) {
+		registry := openTestConcurrencyRegistry(t)
+		release, err := registry.acquire(ctx, "group", false, noCancel)
+		require.NoError(t, err)
+		defer release()
+
+		releaseOther, err := registry.acquire(ctx, "other", false, noCancel)
+		require.NoError(t, err)
+		releaseOther()
+	})
+
+	t.Run("pending-replaced", func(t *testing.T) {
+		registry := openTestConcurrencyRegistry(t)
+		release, err := registry.acquire(ctx, "group", false, noCancel)
+		require.NoError(t, err)
+
+		pending := make(chan error)
+		go func() {
+			_, err := registry.acquire(ctx, "group", false, noCancel)
+			pending <- err
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		newer := make(chan error)
+		go func() {
+			release, err := registry.acquire(ctx, "group", false, noCancel)
+			if err == nil {
+				release()
+			}
+			newer <- err
+		}()
+
+		assert.ErrorIs(t, <-pending, errConcurrencyCancelled)
+		release()
+		assert.NoError(t, <-newer)
+	})
+
+	t.Run("cancel-in-progress", func(t *testing.T) {
+		registry := openTestConcurrencyRegistry(t)
+		cancelled := make(chan struct{})
+		release, err := registry.acquire(ctx, "group", false, func() {
+			close(cancelled)
+		})
+		require.NoError(t, err)
+
+		newer := make(chan error)
+		go func() {
+			release, err := registry.acquire(ctx, "group", true, noCancel)
+			if err == nil {
+				release()
+			}
+			newer <- err
+		}()
+
+		select {
+		case <-cancelled:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the run in progress was not cancelled")
+		}
+		release()
+		assert.NoError(t, <-newer)
+	})
+
+	t.Run("stale-holder", func(t *testing.T) {
+		registry := openTestConcurrencyRegistry(t)
+		require.NoError(t, registry.update("group", func(state *concurrencyState) {
+			state.Holder = "dead"
+			state.Heartbeat = time.Now().Add(-time.Hour)
+		}))
+
+		release, err := registry.acquire(ctx, "group", false, noCancel)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("context-cancelled", func(t *testing.T) {
+		registry := openTestConcurrencyRegistry(t)
+		release, err := registry.acquire(ctx, "group", false, noCancel)
+		require.NoError(t, err)
+		defer release()
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err = registry.acquire(cancelCtx, "group", false, noCancel)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("job-cancelled", func(t *testing.T) {
+		registry := openTestConcurrencyRegistry(t)
+		release, err := registry.acquire(ctx, "group", false, noCancel)
+		require.NoError(t, err)
+		defer release()
+
+		jobCancelCtx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+		time.AfterFunc(50*time.Millisecond, func() {
+			cancel(common.ErrRunCancelled)
+		})
+		_, err = registry.acquire(common.WithJobCancelContext(ctx, jobCancelCtx), "group", false, noCancel)
+		assert.ErrorIs(t, err, common.ErrRunCancelled)
+		assert.True(t, isJobCancelled(jobCancelCtx))
+
+		// the cancelled run is no longer pending
+		require.NoError(t, registry.update("group", func(state *concurrencyState) {
+			assert.Empty(t, state.Pending)
+		}))
+	})
+}
+
+func TestRunConcurrencyWorkflows(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	setConcurrencyPollInterval(t, 50*time.Millisecond)
+
+	var mu sync.Mutex
+	records := map[string]JobRecord{}
+	ctx, cancel := context.WithTimeout(t.Context(), 2*time.Minute)
+	defer cancel()
+
+	// the workflows share a concurrency group, they run one after another instead of waiting for each other
+	table := TestJobFileInfo{workdir, "concurrency-workflows", "push", "", map[string]string{"ubuntu-latest": "-self-hosted"}, secrets}
+	table.runTest(ctx, t, &Config{
+		RecordJob: func(record JobRecord) {
+			mu.Lock()
+			defer mu.Unlock()
+			records[record.JobID] = record
+		},
+	})
+	require.NoError(t, ctx.Err(), "the workflows of the concurrency group did not complete")
+
+	require.Len(t, records, 2)
+	deploy, release := records["deploy"], records["release"]
+	assert.Equal(t, "success", deploy.Result)
+	assert.Equal(t, "success", release.Result)
+	assert.True(t, !deploy.FinishedAt.After(release.StartedAt) || !release.FinishedAt.After(deploy.StartedAt), "the workflows of the concurrency group ran at the same time")
+}
+
+func TestMergeJobResults(t *testing.T) {
+	for _, tt := range []struct {
+		existing, result, merged string
+	}{
+		{"", "success", "success"},
+		{"success", "success", "success"},
+		{"success", "failure", "failure"},
+		{"failure", "success", "failure"},
+		{"success", "cancelled", "cancelled"},
+		{"failure", "cancelled", "failure"},
+		{"cancelled", "failure", "failure"},
+		{"skipped", "success", "success"},
+		{"", "skipped", "skipped"},
+	} {
+		assert.Equal(t, tt.merged, MergeJobResults(tt.existing, tt.result), "%s + %s", tt.existing, tt.result)
+	}
+}
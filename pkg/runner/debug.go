@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+// DebugAction is what a job paused at a breakpoint does next
+type DebugAction int
+
+const (
+	DebugContinue DebugAction = iota // run the step, or go on after the failed step
+	DebugRetry                       // run the failed step again
+	DebugAbort                       // fail the job, the remaining steps only run if their conditions allow it after a failure
+)
+
+// Breakpoint is the step a job paused at
+type Breakpoint struct {
+	Job      string // name of the job
+	Step     string // ID of the step in the form <job>.<step-id>
+	StepName string
+	Err      error // error of the failed step, nil when the job paused before the step
+}
+
+// StepDebugger is asked what to do when a job pauses at a breakpoint, shell opens an interactive shell
+// with the environment of the step in the job container, it is nil if the job container does not support it
+type StepDebugger func(ctx context.Context, breakpoint Breakpoint, shell common.Executor) (DebugAction, error)
+
+var errAbortedAtBreakpoint = errors.New("aborted at a breakpoint")
+
+// useBreakpoints pauses the job before a step listed in BreakBefore runs and after a step failed with BreakOnFailure
+func useBreakpoints(rc *RunContext, step step, executor common.Executor) common.Executor {
+	debugger := rc.Config.Debugger
+	if debugger == nil || (!rc.Config.BreakOnFailure && len(rc.Config.BreakBefore) == 0) {
+		return executor
+	}
+	stepModel := step.getStepModel()
+	id := fmt.Sprintf("%s.%s", rc.Run.JobID, stepModel.ID)
+
+	return func(ctx context.Context) error {
+		if common.Dryrun(ctx) {
+			return executor(ctx)
+		}
+		breakpoint := Breakpoint{Job: rc.String(), Step: id, StepName: stepModel.String()}
+
+		if slices.Contains(rc.Config.BreakBefore, id) {
+			// the environment of the step is set up again when it runs
+			if err := setupEnv(ctx, step); err != nil {
+				return err
+			}
+			// steps which are skipped do not pause the job
+			enabled, err := isStepEnabled(ctx, step.getIfExpression(ctx, stepStageMain), step, stepStageMain)
+			if err != nil || !enabled {
+				return executor(ctx)
+			}
+			action, err := debugger(ctx, breakpoint, stepShell(ctx, rc, step))
+			if err != nil {
+				return err
+			}
+			if action == DebugAbort {
+				return errAbortedAtBreakpoint
+			}
+		}
+
+		for {
+			err := executor(ctx)
+			if err == nil || !rc.Config.BreakOnFailure || ctx.Err() != nil {
+				return err
+			}
+			breakpoint.Err = err
+			action, debugErr := debugger(ctx, breakpoint, stepShell(ctx, rc, step))
+			if debugErr != nil {
+				return errors.Join(err, debugErr)
+			}
+			switch action {
+			case DebugRetry:
+				common.Logger(ctx).Infof("Retrying step '%s'", stepModel)
+			case DebugAbort:
+				return errors.Join(err, errAbortedAtBreakpoint)
+			default:
+				return err
+			}
+		}
+	}
+}
+
+// stepShell opens an interactive shell in the job container with the environment of the step,
+// including the variables of GITHUB_ENV and the directories of GITHUB_PATH
+func stepShell(ctx context.Context, rc *RunContext, step step) common.Executor {
+	shell, ok := rc.JobContainer.(container.InteractiveShell)
+	if !ok {
+		return nil
+	}
+	env := maps.Clone(*step.getEnv())
+	rc.ApplyExtraPath(ctx, &env)
+	return shell.Shell(env, rc.ExprEval.Interpolate(ctx, step.getStepModel().WorkingDirectory), os.Stdin, os.Stdout)
+}
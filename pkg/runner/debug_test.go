@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunBreakpoints(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	for name, tt := range map[string]struct {
+		actions      map[string]DebugAction
+		errorMessage string
+		breakpoints  []string
+	}{
+		"retry":           {map[string]DebugAction{"test.flaky": DebugRetry}, "", []string{"test.flaky failed", "test.deploy", "test.after"}},
+		"continue":        {map[string]DebugAction{"test.flaky": DebugContinue}, "Job 'test' failed", []string{"test.flaky failed", "test.after"}},
+		"abort-on-retry":  {map[string]DebugAction{"test.flaky": DebugRetry, "test.deploy": DebugAbort}, "Job 'test' failed", []string{"test.flaky failed", "test.deploy", "test.after"}},
+		"abort-on-failed": {map[string]DebugAction{"test.flaky": DebugAbort}, "Job 'test' failed", []string{"test.flaky failed", "test.after"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			breakpoints := []string{}
+			table := TestJobFileInfo{workdir, "breakpoints", "push", tt.errorMessage, map[string]string{"ubuntu-latest": "-self-hosted"}, secrets}
+			table.runTest(t.Context(), t, &Config{
+				BreakOnFailure: true,
+				BreakBefore:    []string{"test.deploy", "test.after"},
+				Debugger: func(_ context.Context, breakpoint Breakpoint, shell common.Executor) (DebugAction, error) {
+					assert.NotNil(t, shell, "the host environment supports interactive shells")
+					if breakpoint.Err != nil {
+						breakpoints = append(breakpoints, breakpoint.Step+" failed")
+					} else {
+						breakpoints = append(breakpoints, breakpoint.Step)
+					}
+					return tt.actions[breakpoint.Step], nil
+				},
+			})
+			assert.Equal(t, tt.breakpoints, breakpoints)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/sirupsen/logrus"
+)
+
+// EnvironmentApprover decides whether a job may deploy to a protected environment
+type EnvironmentApprover func(ctx context.Context, job string, environment string) (bool, error)
+
+// environmentSecrets returns the secrets of the deployment environment of the job, they override the repository secrets
+func (rc *RunContext) environmentSecrets() map[string]string {
+	return environmentValues(rc.Config.EnvironmentSecrets, rc.EnvironmentName)
+}
+
+// environmentVars returns the vars of the deployment environment of the job, they override the repository vars
+func (rc *RunContext) environmentVars() map[string]string {
+	return environmentValues(rc.Config.EnvironmentVars, rc.EnvironmentName)
+}
+
+// environmentValues returns the values of an environment, the names of environments are case insensitive like on GitHub
+func environmentValues(values map[string]map[string]string, environment string) map[string]string {
+	if environment == "" {
+		return nil
+	}
+	if v, ok := values[environment]; ok {
+		return v
+	}
+	for name, v := range values {
+		if strings.EqualFold(name, environment) {
+			return v
+		}
+	}
+	return nil
+}
+
+// EnvironmentNames returns the sorted names of the environments which have secrets or vars
+func EnvironmentNames(values ...map[string]map[string]string) []string {
+	names := []string{}
+	for _, v := range values {
+		for name := range v {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// overlay returns a copy of base with the values of override, or base itself if there is nothing to override
+func overlay(base map[string]string, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	maps.Copy(merged, base)
+	maps.Copy(merged, override)
+	return merged
+}
+
+// useEnvironment resolves the deployment environment of the job and runs the job once the environment is approved
+func (rc *RunContext) useEnvironment(executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		environment := rc.Run.Job().DeploymentEnvironment()
+		if environment == nil {
+			return executor(ctx)
+		}
+
+		rc.EnvironmentName = rc.ExprEval.Interpolate(ctx, environment.Name)
+		if rc.EnvironmentName == "" {
+			return executor(ctx)
+		}
+		// rebuild the evaluator to expose the secrets and vars of the environment
+		rc.ExprEval = rc.NewExpressionEvaluator(ctx)
+		for _, secret := range rc.environmentSecrets() {
+			rc.AddMask(secret)
+		}
+
+		logger := common.Logger(ctx)
+		if rc.Config.ApproveEnvironment != nil && !common.Dryrun(ctx) {
+			approved, err := rc.Config.ApproveEnvironment(ctx, rc.String(), rc.EnvironmentName)
+			if err != nil {
+				return fmt.Errorf("unable to approve the environment '%s': %w", rc.EnvironmentName, err)
+			}
+			if !approved {
+				rc.result(MergeJobResults(rc.Run.Job().Result, "failure"))
+				logger.WithField("jobResult", "failure").Infof("\U0001F3C1  Job failed, the deployment to the environment '%s' was rejected", rc.EnvironmentName)
+				return nil
+			}
+		}
+		logger.Infof("\U0001F310  Deploying to the environment '%s'", rc.EnvironmentName)
+
+		return executor(ctx)
+	}
+}
+
+// setEnvironmentURL evaluates the url of the deployment environment at the end of the job, when the step outputs it refers to are known
+func setEnvironmentURL(ctx context.Context, rc *RunContext) {
+	environment := rc.Run.Job().DeploymentEnvironment()
+	if rc.EnvironmentName == "" || environment == nil || environment.URL == "" {
+		return
+	}
+	rc.EnvironmentURL = rc.NewExpressionEvaluator(ctx).Interpolate(ctx, environment.URL)
+	if rc.EnvironmentURL != "" {
+		common.Logger(ctx).WithFields(logrus.Fields{
+			"environment":    rc.EnvironmentName,
+			"environmentUrl": rc.EnvironmentURL,
+		}).Infof("\U0001F310  Environment %s: %s", rc.EnvironmentName, rc.EnvironmentURL)
+	}
+}
@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverlay(t *testing.T) {
+	base := map[string]string{"A": "base", "B": "base"}
+	assert.Equal(t, base, overlay(base, nil))
+	assert.Equal(t, map[string]string{"A": "base", "B": "override", "C": "override"}, overlay(base, map[string]string{"B": "override", "C": "override"}))
+	assert.Equal(t, map[string]string{"A": "base", "B": "base"}, base, "the base map must not be modified")
+}
+
+func TestEnvironmentValues(t *testing.T) {
+	values := map[string]map[string]string{
+		"production": {"TARGET": "production"},
+		"Staging":    {"TARGET": "staging"},
+	}
+	assert.Equal(t, map[string]string{"TARGET": "production"}, environmentValues(values, "production"))
+	assert.Equal(t, map[string]string{"TARGET": "production"}, environmentValues(values, "Production"))
+	assert.Equal(t, map[string]string{"TARGET": "staging"}, environmentValues(values, "staging"))
+	assert.Nil(t, environmentValues(values, "development"))
+	assert.Nil(t, environmentValues(values, ""))
+}
+
+func TestRunEnvironment(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	for name, tt := range map[string]struct {
+		approved     bool
+		errorMessage string
+		jobResults   []interface{}
+	}{
+		"approved": {true, "", []interface{}{"success", "success"}},
+		"rejected": {false, "Job 'production' failed", []interface{}{"success", "failure"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			factory := &captureJobLoggerFactory{}
+			logger := logrus.New()
+			logger.SetOutput(&factory.buffer)
+			logger.SetFormatter(&logrus.JSONFormatter{})
+
+			approvals := []string{}
+			environments := map[string][]string{}
+			table := TestJobFileInfo{workdir, "environment", "push", tt.errorMessage, map[string]string{"ubuntu-latest": "-self-hosted"}, secrets}
+			table.runTest(common.WithLogger(WithJobLoggerFactory(t.Context(), factory), logger), t, &Config{
+				Secrets: map[string]string{"REPO_SECRET": "repo", "DEPLOY_TOKEN": "repo-token"},
+				EnvironmentSecrets: map[string]map[string]string{
+					"staging":    {"DEPLOY_TOKEN": "staging-token"},
+					"production": {"DEPLOY_TOKEN": "production-token"},
+				},
+				EnvironmentVars: map[string]map[string]string{
+					"staging":    {"TARGET": "staging"},
+					"production": {"TARGET": "production"},
+				},
+				RecordJob: func(record JobRecord) {
+					environments[record.JobID] = []string{record.Environment, record.EnvironmentURL}
+				},
+				ApproveEnvironment: func(_ context.Context, _ string, environment string) (bool, error) {
+					approvals = append(approvals, environment)
+					return !strings.EqualFold(environment, "production") || tt.approved, nil
+				},
+			})
+
+			jobResults := []interface{}{}
+			environmentURLs := []interface{}{}
+			scan := bufio.NewScanner(&factory.buffer)
+			for scan.Scan() {
+				entry := map[string]interface{}{}
+				if json.Unmarshal(scan.Bytes(), &entry) == nil {
+					if val, ok := entry["jobResult"]; ok {
+						jobResults = append(jobResults, val)
+					}
+					if val, ok := entry["environmentUrl"]; ok {
+						environmentURLs = append(environmentURLs, val)
+					}
+				}
+			}
+			assert.Equal(t, []string{"staging", "Production"}, approvals)
+			assert.Equal(t, tt.jobResults, jobResults)
+			assert.Equal(t, []interface{}{"https://staging.example.com"}, environmentURLs)
+			assert.Equal(t, []string{"staging", "https://staging.example.com"}, environments["staging"])
+			assert.Equal(t, []string{"Production", ""}, environments["production"])
+		})
+	}
+}
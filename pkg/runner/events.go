@@ -0,0 +1,217 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+// EventHandler receives the events of the lifecycle of a run as they happen, it is called from the goroutines of the jobs
+type EventHandler func(event Event)
+
+// EventType is the type of an Event
+type EventType string
+
+const (
+	EventPlanCreated      EventType = "plan_created"      // Stages
+	EventJobStarted       EventType = "job_started"       // job fields
+	EventJobFinished      EventType = "job_finished"      // job fields, Result, Outputs, Environment, EnvironmentURL, Reused
+	EventStepStarted      EventType = "step_started"      // job and step fields
+	EventStepFinished     EventType = "step_finished"     // job and step fields, Outcome, Conclusion, Duration
+	EventOutputSet        EventType = "output_set"        // job and step fields, Name, Value
+	EventAnnotation       EventType = "annotation"        // job fields, Annotation
+	EventArtifactUploaded EventType = "artifact_uploaded" // Artifact
+	EventRunFinished      EventType = "run_finished"      // Result, Error
+)
+
+// Event is an event of the lifecycle of a run, only the fields of its type are set.
+// The job fields identify a run of a job, Job is the name of the job in the log including its matrix.
+type Event struct {
+	Type           EventType              `json:"type"`
+	Time           time.Time              `json:"time"`
+	Workflow       string                 `json:"workflow,omitempty"` // file of the workflow
+	JobID          string                 `json:"jobID,omitempty"`
+	Job            string                 `json:"job,omitempty"`
+	Matrix         map[string]interface{} `json:"matrix,omitempty"`
+	StepID         string                 `json:"stepID,omitempty"`
+	Step           string                 `json:"step,omitempty"`
+	Stages         [][]PlannedJob         `json:"stages,omitempty"`
+	Result         string                 `json:"result,omitempty"`
+	Outcome        string                 `json:"outcome,omitempty"`
+	Conclusion     string                 `json:"conclusion,omitempty"`
+	Duration       time.Duration          `json:"duration,omitempty"`
+	Name           string                 `json:"name,omitempty"`
+	Value          string                 `json:"value,omitempty"`
+	Outputs        map[string]string      `json:"outputs,omitempty"`
+	Environment    string                 `json:"environment,omitempty"`
+	EnvironmentURL string                 `json:"environmentUrl,omitempty"`
+	Reused         bool                   `json:"reused,omitempty"`
+	Annotation     *Annotation            `json:"annotation,omitempty"`
+	Artifact       *Artifact              `json:"artifact,omitempty"`
+	Error          string                 `json:"error,omitempty"`
+}
+
+// PlannedJob is a job of a stage of the plan
+type PlannedJob struct {
+	Workflow string `json:"workflow"` // file of the workflow
+	JobID    string `json:"jobID"`
+	Name     string `json:"name"`
+}
+
+// Artifact is an artifact uploaded to the artifact server
+type Artifact struct {
+	Name  string `json:"name"`
+	Size  int64  `json:"size"`
+	RunID string `json:"runID"`
+}
+
+// emit sends an event to the EventHandler of the config, if any
+func (config *Config) emit(event Event) {
+	if config == nil || config.Events == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	config.Events(event)
+}
+
+// emit sends an event with the job fields of the run context
+func (rc *RunContext) emit(event Event) {
+	if rc.Run != nil {
+		event.Workflow = rc.Run.Workflow.File
+		event.JobID = rc.Run.JobID
+		event.Job = rc.String()
+	}
+	if len(rc.Matrix) > 0 {
+		event.Matrix = rc.Matrix
+	}
+	rc.Config.emit(event)
+}
+
+// usePlanEvents emits the plan before the executor runs and the result of the run after it
+func usePlanEvents(config *Config, plan *model.Plan, executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		stages := [][]PlannedJob{}
+		for _, stage := range plan.Stages {
+			jobs := []PlannedJob{}
+			for _, run := range stage.Runs {
+				jobs = append(jobs, PlannedJob{Workflow: run.Workflow.File, JobID: run.JobID, Name: run.String()})
+			}
+			stages = append(stages, jobs)
+		}
+		config.emit(Event{Type: EventPlanCreated, Stages: stages})
+
+		err := executor(ctx)
+		event := Event{Type: EventRunFinished, Result: "success"}
+		switch {
+		case errors.Is(err, context.Canceled) || ctx.Err() != nil || isJobCancelled(common.JobCancelContext(ctx)):
+			event.Result = "cancelled"
+		case err != nil:
+			event.Result = "failure"
+		}
+		if err != nil {
+			event.Error = err.Error()
+		}
+		config.emit(event)
+		return err
+	}
+}
+
+// useJobEvents emits the start and the end of a job and the annotations reported by its steps
+func useJobEvents(rc *RunContext, executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		if rc.Config == nil || rc.Config.Events == nil {
+			return executor(ctx)
+		}
+		hook := &eventHook{rc: rc, masker: valueMasker(rc.Config.InsecureSecrets, rc.Config.Secrets)}
+		removeHook := addJobHook(ctx, hook)
+		rc.emit(Event{Type: EventJobStarted})
+
+		err := executor(ctx)
+		removeHook()
+
+		event := Event{Type: EventJobFinished, Result: hook.jobResult(), Environment: rc.EnvironmentName, EnvironmentURL: rc.EnvironmentURL}
+		if rc.Run != nil {
+			event.Outputs = map[string]string{}
+			for name, value := range rc.Run.Job().Outputs {
+				event.Outputs[name] = hook.mask(ctx, value)
+			}
+		}
+		rc.emit(event)
+		return err
+	}
+}
+
+// eventHook is a hook of the job logger which emits the annotations of a job and keeps its result
+type eventHook struct {
+	mu     sync.Mutex
+	rc     *RunContext
+	masker entryProcessor
+	result string
+}
+
+func (h *eventHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *eventHook) Fire(entry *logrus.Entry) error {
+	if result, ok := entry.Data["jobResult"].(string); ok {
+		h.mu.Lock()
+		h.result = result
+		h.mu.Unlock()
+	}
+	if annotation, ok := newAnnotation(entry, h.masker); ok {
+		h.rc.emit(Event{Type: EventAnnotation, StepID: annotation.StepID, Step: annotation.Step, Annotation: &annotation})
+	}
+	return nil
+}
+
+func (h *eventHook) jobResult() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.result
+}
+
+func (h *eventHook) mask(ctx context.Context, value string) string {
+	return h.masker(&logrus.Entry{Context: ctx, Message: value}).Message
+}
+
+// useStepEvents emits the start and the end of the main stage of a step
+func useStepEvents(rc *RunContext, stepModel *model.Step, stage stepStage, executor common.Executor) common.Executor {
+	if stage != stepStageMain {
+		return executor
+	}
+	return func(ctx context.Context) error {
+		if rc.Config == nil || rc.Config.Events == nil {
+			return executor(ctx)
+		}
+		step := rc.ExprEval.Interpolate(ctx, stepModel.String())
+		rc.emit(Event{Type: EventStepStarted, StepID: stepModel.ID, Step: step})
+		startedAt := time.Now()
+
+		err := executor(ctx)
+
+		event := Event{Type: EventStepFinished, StepID: stepModel.ID, Step: step, Duration: time.Since(startedAt)}
+		if result, ok := rc.StepResults[stepModel.ID]; ok {
+			event.Outcome = result.Outcome.String()
+			event.Conclusion = result.Conclusion.String()
+		}
+		rc.emit(event)
+		return err
+	}
+}
+
+// emitOutput emits an output set by a step, masked like the log
+func (rc *RunContext) emitOutput(ctx context.Context, stepID string, name string, value string) {
+	if rc.Config == nil || rc.Config.Events == nil {
+		return
+	}
+	masker := valueMasker(rc.Config.InsecureSecrets, rc.Config.Secrets)
+	rc.emit(Event{Type: EventOutputSet, StepID: stepID, Name: name, Value: masker(&logrus.Entry{Context: ctx, Message: value}).Message})
+}
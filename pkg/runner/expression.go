@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"maps"
 	"path"
 	"reflect"
 	"regexp"
@@ -36,14 +37,7 @@ func (rc *RunContext) NewExpressionEvaluatorWithEnv(ctx context.Context, env map
 
 	// todo: cleanup EvaluationEnvironment creation
 	using := make(map[string]exprparser.Needs)
-	strategy := make(map[string]interface{})
 	if rc.Run != nil {
-		job := rc.Run.Job()
-		if job != nil && job.Strategy != nil {
-			strategy["fail-fast"] = job.Strategy.FailFast
-			strategy["max-parallel"] = job.Strategy.MaxParallel
-		}
-
 		jobs := rc.Run.Workflow.Jobs
 		jobNeeds := rc.Run.Job().Needs()
 
@@ -85,7 +79,7 @@ func (rc *RunContext) NewExpressionEvaluatorWithEnv(ctx context.Context, env map
 		Steps:     rc.getStepsContext(),
 		Secrets:   getWorkflowSecrets(ctx, rc),
 		Vars:      getWorkflowVars(ctx, rc),
-		Strategy:  strategy,
+		Strategy:  rc.getStrategyContext(),
 		Matrix:    rc.Matrix,
 		Needs:     using,
 		Inputs:    inputs,
@@ -122,13 +116,6 @@ func (rc *RunContext) NewStepExpressionEvaluatorExt(ctx context.Context, step st
 
 func (rc *RunContext) newStepExpressionEvaluator(ctx context.Context, step step, _ *model.GithubContext, inputs map[string]interface{}) ExpressionEvaluator {
 	// todo: cleanup EvaluationEnvironment creation
-	job := rc.Run.Job()
-	strategy := make(map[string]interface{})
-	if job.Strategy != nil {
-		strategy["fail-fast"] = job.Strategy.FailFast
-		strategy["max-parallel"] = job.Strategy.MaxParallel
-	}
-
 	jobs := rc.Run.Workflow.Jobs
 	jobNeeds := rc.Run.Job().Needs()
 
@@ -147,7 +134,7 @@ func (rc *RunContext) newStepExpressionEvaluator(ctx context.Context, step step,
 		Steps:    rc.getStepsContext(),
 		Secrets:  getWorkflowSecrets(ctx, rc),
 		Vars:     getWorkflowVars(ctx, rc),
-		Strategy: strategy,
+		Strategy: rc.getStrategyContext(),
 		Matrix:   rc.Matrix,
 		Needs:    using,
 		// todo: should be unavailable
@@ -500,17 +487,10 @@ func getEvaluatorInputs(ctx context.Context, rc *RunContext, step step, ghc *mod
 	if rc.caller == nil && ghc.EventName == "workflow_dispatch" {
 		config := rc.Run.Workflow.WorkflowDispatchConfig()
 		if config != nil && config.Inputs != nil {
-			for k, v := range config.Inputs {
-				value := nestedMapLookup(ghc.Event, "inputs", k)
-				if value == nil {
-					value = v.Default
-				}
-				if v.Type == "boolean" {
-					inputs[k] = value == "true"
-				} else {
-					inputs[k] = value
-				}
-			}
+			provided, _ := ghc.Event["inputs"].(map[string]interface{})
+			// invalid inputs are reported before the jobs run
+			dispatchInputs, _ := config.ResolveInputs(provided, EnvironmentNames(rc.Config.EnvironmentSecrets, rc.Config.EnvironmentVars))
+			maps.Copy(inputs, dispatchInputs)
 		}
 	}
 
@@ -586,9 +566,9 @@ func getWorkflowSecrets(ctx context.Context, rc *RunContext) map[string]string {
 		return secrets
 	}
 
-	return rc.Config.Secrets
+	return overlay(rc.Config.Secrets, rc.environmentSecrets())
 }
 
 func getWorkflowVars(_ context.Context, rc *RunContext) map[string]string {
-	return rc.Config.Vars
+	return overlay(rc.Config.Vars, rc.environmentVars())
 }
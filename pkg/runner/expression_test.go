@@ -42,7 +42,9 @@ func createRunContext(t *testing.T) *RunContext {
 				Jobs: map[string]*model.Job{
 					"job1": {
 						Strategy: &model.Strategy{
-							RawMatrix: yml,
+							FailFast:    true,
+							MaxParallel: 2,
+							RawMatrix:   yml,
 						},
 					},
 				},
@@ -52,6 +54,8 @@ func createRunContext(t *testing.T) *RunContext {
 			"os":  "Linux",
 			"foo": "bar",
 		},
+		JobIndex: 1,
+		JobTotal: 4,
 		StepResults: map[string]*model.StepResult{
 			"idwithnothing": {
 				Conclusion: model.StepStatusSuccess,
@@ -122,6 +126,10 @@ func TestEvaluateRunContext(t *testing.T) {
 		{"job.status", "success", ""},
 		{"matrix.os", "Linux", ""},
 		{"matrix.foo", "bar", ""},
+		{"strategy.fail-fast", true, ""},
+		{"strategy.max-parallel", 2, ""},
+		{"strategy.job-index", 1, ""},
+		{"strategy.job-total", 4, ""},
 		{"env.key", "value", ""},
 		{"secrets.CASE_INSENSITIVE_SECRET", "value", ""},
 		{"secrets.case_insensitive_secret", "value", ""},
@@ -2,7 +2,9 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/nektos/act/pkg/common"
@@ -63,6 +65,11 @@ func newJobExecutor(info jobInfo, sf stepFactory, rc *RunContext) common.Executo
 		return err
 	}
 
+	selected, err := selectSteps(rc, infoSteps)
+	if err != nil {
+		return common.NewErrorExecutor(err)
+	}
+
 	for i, stepModel := range infoSteps {
 		if stepModel == nil {
 			return func(_ context.Context) error {
@@ -79,9 +86,14 @@ func newJobExecutor(info jobInfo, sf stepFactory, rc *RunContext) common.Executo
 			return common.NewErrorExecutor(err)
 		}
 
+		if selected != nil && !selected[i] {
+			steps = append(steps, useStepLogger(rc, stepModel, stepStageMain, skipStep(rc, stepModel)))
+			continue
+		}
+
 		preSteps = append(preSteps, useStepLogger(rc, stepModel, stepStagePre, step.pre().ThenError(setJobError)))
 
-		stepExec := step.main()
+		stepExec := useBreakpoints(rc, step, step.main())
 		steps = append(steps, useStepLogger(rc, stepModel, stepStageMain, func(ctx context.Context) error {
 			err := stepExec(ctx)
 			if err != nil {
@@ -101,6 +113,13 @@ func newJobExecutor(info jobInfo, sf stepFactory, rc *RunContext) common.Executo
 		}
 	}
 
+	if postExecutor == nil {
+		// no step of the job is selected
+		postExecutor = func(_ context.Context) error {
+			return nil
+		}
+	}
+
 	var stopContainerExecutor common.Executor = func(ctx context.Context) error {
 		jobError := common.JobError(ctx)
 		var err error
@@ -122,6 +141,7 @@ func newJobExecutor(info jobInfo, sf stepFactory, rc *RunContext) common.Executo
 		jobError := common.JobError(ctx)
 		setJobResult(ctx, info, rc, jobError == nil)
 		setJobOutputs(ctx, rc)
+		setEnvironmentURL(ctx, rc)
 		return nil
 	}
 
@@ -129,13 +149,17 @@ func newJobExecutor(info jobInfo, sf stepFactory, rc *RunContext) common.Executo
 	pipeline = append(pipeline, preSteps...)
 	pipeline = append(pipeline, steps...)
 
-	return common.NewPipelineExecutor(
+	return useJobEvents(rc, useJobTimeout(rc, common.NewPipelineExecutor(
 		common.NewFieldExecutor("step", "Set up job", common.NewFieldExecutor("stepid", []string{"--setup-job"},
 			common.NewPipelineExecutor(common.NewInfoExecutor("\u2B50 Run Set up job"), info.startContainer(), rc.InitializeNodeTool()).
 				Then(common.NewFieldExecutor("stepResult", model.StepStatusSuccess, common.NewInfoExecutor("  \u2705  Success - Set up job"))).
 				ThenError(setJobError).OnError(common.NewFieldExecutor("stepResult", model.StepStatusFailure, common.NewInfoExecutor("  \u274C  Failure - Set up job"))))),
 		common.NewPipelineExecutor(pipeline...).
 			Finally(func(ctx context.Context) error { //nolint:contextcheck
+				if common.IsForceCancelled(ctx) {
+					common.Logger(ctx).Warnf("Skipping the post steps, the run was stopped right away")
+					return nil
+				}
 				var cancel context.CancelFunc
 				if ctx.Err() == context.Canceled {
 					// in case of an aborted run, we still should execute the
@@ -151,31 +175,90 @@ func newJobExecutor(info jobInfo, sf stepFactory, rc *RunContext) common.Executo
 					Finally(
 						info.interpolateOutputs().Finally(info.closeContainer()).Then(common.NewFieldExecutor("stepResult", model.StepStatusSuccess, common.NewInfoExecutor("  \u2705  Success - Complete job"))).
 							OnError(common.NewFieldExecutor("stepResult", model.StepStatusFailure, common.NewInfoExecutor("  \u274C  Failure - Complete job"))),
-					))))).Finally(setJobResultExecutor)
+					)))))).Finally(setJobResultExecutor))
+}
+
+// defaultJobTimeout is the maximum execution time of a job without timeout-minutes, as on GitHub
+const defaultJobTimeout = 360 * time.Minute
+
+var errJobTimeout = errors.New("job timeout exceeded")
+
+func evaluateJobTimeout(ctx context.Context, rc *RunContext) time.Duration {
+	timeout := rc.NewExpressionEvaluator(ctx).Interpolate(ctx, rc.Run.Job().TimeoutMinutes)
+	if timeout == "" {
+		return defaultJobTimeout
+	}
+	timeOutMinutes, err := strconv.ParseFloat(timeout, 64)
+	if err != nil || timeOutMinutes <= 0 {
+		common.Logger(ctx).Warnf("Invalid timeout-minutes '%s', using the default of %v minutes", timeout, defaultJobTimeout.Minutes())
+		return defaultJobTimeout
+	}
+	return time.Duration(timeOutMinutes * float64(time.Minute))
+}
+
+// useJobTimeout cancels the job once its timeout-minutes are exceeded.
+// The timeout cancels the job cancellation context, so the running step is stopped, while steps
+// using always() and post steps are still executed, and the job fails with the timeout as its error.
+func useJobTimeout(rc *RunContext, executor common.Executor) common.Executor {
+	return func(ctx context.Context) error {
+		timeout := evaluateJobTimeout(ctx, rc)
+
+		parent := common.JobCancelContext(ctx)
+		if parent == nil {
+			parent = ctx
+		}
+		cancelCtx, cancel := context.WithTimeoutCause(parent, timeout, errJobTimeout)
+		defer cancel()
+
+		err := executor(common.WithJobCancelContext(ctx, cancelCtx))
+		if errors.Is(context.Cause(cancelCtx), errJobTimeout) {
+			timeoutErr := fmt.Errorf("the job has exceeded the maximum execution time of %v minutes", timeout.Minutes())
+			common.Logger(ctx).Errorf("%v", timeoutErr)
+			common.SetJobError(ctx, timeoutErr)
+		}
+		return err
+	}
 }
 
 func setJobResult(ctx context.Context, info jobInfo, rc *RunContext, success bool) {
 	logger := common.Logger(ctx)
 
 	jobResult := "success"
-	// we have only one result for a whole matrix build, so we need
-	// to keep an existing result state if we run a matrix
-	if len(info.matrix()) > 0 && rc.Run.Job().Result != "" {
-		jobResult = rc.Run.Job().Result
-	}
-
 	if !success {
+		job := rc.Run.Job()
+		if rc.isContinueOnError(ctx) {
+			logger.Infof("Failed but continue-on-error lets the workflow run proceed")
+			// a single failed job of a matrix without continue-on-error fails the whole matrix
+			if job.Result != "failure" {
+				job.ContinuedOnError = true
+			}
+		} else {
+			job.ContinuedOnError = false
+		}
 		jobResult = "failure"
 	}
+	if rc.Cancelled && isJobCancelled(common.JobCancelContext(ctx)) {
+		jobResult = "cancelled"
+	}
+
+	result := jobResult
+	// we have only one result for a whole matrix build, so we need
+	// to keep an existing result state if we run a matrix
+	if len(info.matrix()) > 0 {
+		result = MergeJobResults(rc.Run.Job().Result, jobResult)
+	}
 
-	info.result(jobResult)
+	info.result(result)
 	if rc.caller != nil {
 		// set reusable workflow job result
-		rc.caller.runContext.result(jobResult)
+		rc.caller.runContext.result(result)
 	}
 
 	jobResultMessage := "succeeded"
-	if jobResult != "success" {
+	switch jobResult {
+	case "cancelled":
+		jobResultMessage = "cancelled"
+	case "failure":
 		jobResultMessage = "failed"
 	}
 
@@ -214,6 +297,6 @@ func useStepLogger(rc *RunContext, stepModel *model.Step, stage stepStage, execu
 		oldout, olderr := rc.JobContainer.ReplaceLogWriter(logWriter, logWriter)
 		defer rc.JobContainer.ReplaceLogWriter(oldout, olderr)
 
-		return executor(ctx)
+		return useStepEvents(rc, stepModel, stage, executor)(ctx)
 	}
 }
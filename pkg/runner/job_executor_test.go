@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/container"
@@ -337,3 +338,69 @@ func TestNewJobExecutor(t *testing.T) {
 		})
 	}
 }
+
+func TestNewJobExecutorTimeout(t *testing.T) {
+	ctx := common.WithJobErrorContainer(context.Background())
+	jim := &jobInfoMock{}
+	sfm := &stepFactoryMock{}
+	rc := &RunContext{
+		JobContainer: &jobContainerMock{},
+		Run: &model.Run{
+			JobID: "test",
+			Workflow: &model.Workflow{
+				Jobs: map[string]*model.Job{
+					"test": {
+						TimeoutMinutes: "${{ 0.001 }}",
+					},
+				},
+			},
+		},
+		Config:           &Config{},
+		nodeToolFullPath: "node",
+	}
+	rc.ExprEval = rc.NewExpressionEvaluator(ctx)
+	executorOrder := make([]string, 0)
+
+	stepModel := &model.Step{ID: "1"}
+	sm := &stepMock{}
+	sfm.On("newStep", stepModel, rc).Return(sm, nil)
+	sm.On("pre").Return(func(_ context.Context) error {
+		return nil
+	})
+	sm.On("main").Return(func(ctx context.Context) error {
+		executorOrder = append(executorOrder, "step1")
+		select {
+		case <-common.JobCancelContext(ctx).Done():
+			return context.Canceled
+		case <-time.After(10 * time.Second):
+			return nil
+		}
+	})
+	sm.On("post").Return(func(_ context.Context) error {
+		executorOrder = append(executorOrder, "post1")
+		return nil
+	})
+
+	jim.On("steps").Return([]*model.Step{stepModel})
+	jim.On("matrix").Return(map[string]interface{}{})
+	jim.On("startContainer").Return(func(_ context.Context) error {
+		return nil
+	})
+	jim.On("interpolateOutputs").Return(func(_ context.Context) error {
+		return nil
+	})
+	jim.On("closeContainer").Return(func(_ context.Context) error {
+		return nil
+	})
+	jim.On("result", "failure")
+
+	executor := newJobExecutor(jim, sfm, rc)
+	err := executor(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"step1", "post1"}, executorOrder)
+	assert.ErrorContains(t, common.JobError(ctx), "exceeded the maximum execution time of 0.001 minutes")
+
+	jim.AssertExpectations(t)
+	sfm.AssertExpectations(t)
+	sm.AssertExpectations(t)
+}
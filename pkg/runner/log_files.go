@@ -0,0 +1,260 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ansiEscape       = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+	invalidFileChars = regexp.MustCompile(`[/\\:*?"<>|\x00-\x1f]`)
+)
+
+// maxLogFileNameLength cuts the names of jobs and steps, e.g. of steps named after their script
+const maxLogFileNameLength = 100
+
+// jobLogFiles is a hook of the job logger which writes the log of a job to <dir>/<workflow>/<n>_<job>.txt
+// and the log of each of its steps to <dir>/<workflow>/<job>/<n>_<step>.txt, the steps are numbered in the order they ran
+type jobLogFiles struct {
+	mu     sync.Mutex
+	remove func()
+	dir    string // directory of the step files
+	masker entryProcessor
+	file   *os.File
+	steps  map[string]*os.File
+}
+
+type logDirsContextKey struct{}
+
+// logDirs are the directories of the workflows written by a run, with the number of jobs written to each.
+// The jobs of a workflow are numbered in the order they start, like the log files of the archive of GitHub.
+type logDirs struct {
+	mu        sync.Mutex
+	workflows map[string]string // workflow file by directory
+	jobs      map[string]int
+}
+
+func newLogDirs() *logDirs {
+	return &logDirs{workflows: map[string]string{}, jobs: map[string]int{}}
+}
+
+// withLogDirs numbers the jobs of the run in the directories of their workflows, a nested run like the one
+// of a reusable workflow numbers its jobs along with those of its caller
+func withLogDirs(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(logDirsContextKey{}).(*logDirs); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, logDirsContextKey{}, newLogDirs())
+}
+
+// workflowDir returns the directory of a workflow in the log directory, named after the workflow, or after its file
+// if another workflow of the run has the same name
+func (d *logDirs) workflowDir(logDir string, name string, file string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := 0; ; i++ {
+		var dir string
+		switch i {
+		case 0:
+			dir = logFileName(name)
+		case 1:
+			dir = logFileName(file)
+		default:
+			dir = logFileName(fmt.Sprintf("%s-%d", file, i))
+		}
+		dir = filepath.Join(logDir, dir)
+		// the names are sanitized, the directory can't be the log directory itself or outside of it
+		if rel, err := filepath.Rel(logDir, dir); err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("the log directory of the workflow '%s' is outside of %s", name, logDir)
+		}
+		if owner, ok := d.workflows[dir]; !ok || owner == file {
+			d.workflows[dir] = file
+			return dir, nil
+		}
+	}
+}
+
+// nextJob returns the number of the next job of the directory of a workflow. The logs of the jobs of a previous run
+// are removed before the first job, so that the directory only holds the logs of this run when the log directory
+// is reused. Only the files written for jobs are removed, other files in the directory are kept.
+func (d *logDirs) nextJob(dir string) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n, ok := d.jobs[dir]
+	if !ok {
+		if err := removeJobLogFiles(dir); err != nil {
+			return 0, err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, err
+	}
+	d.jobs[dir] = n + 1
+	return n + 1, nil
+}
+
+// logFilePattern matches the <n>_<name>.txt files of jobs and steps
+var logFilePattern = regexp.MustCompile(`^\d+_(.+)\.txt$`)
+
+// removeJobLogFiles removes the <n>_<job>.txt files of a directory and the <n>_<step>.txt files of their job
+// directories, the job directories are removed once they are empty
+func removeJobLogFiles(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		match := logFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil || entry.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+		jobDir := filepath.Join(dir, match[1])
+		steps, err := os.ReadDir(jobDir)
+		if err != nil {
+			continue
+		}
+		for _, step := range steps {
+			if !step.IsDir() && logFilePattern.MatchString(step.Name()) {
+				if err := os.Remove(filepath.Join(jobDir, step.Name())); err != nil {
+					return err
+				}
+			}
+		}
+		// a job directory which still holds other files is kept
+		_ = os.Remove(jobDir)
+	}
+	return nil
+}
+
+// withJobLogFiles starts writing the log of the job of the run context to files in the directory
+func withJobLogFiles(ctx context.Context, rc *RunContext, logDir string) (*jobLogFiles, error) {
+	dirs, ok := ctx.Value(logDirsContextKey{}).(*logDirs)
+	if !ok {
+		dirs = newLogDirs()
+	}
+	dir, err := dirs.workflowDir(logDir, rc.Run.Workflow.Name, rc.Run.Workflow.File)
+	if err != nil {
+		return nil, err
+	}
+	name := logFileName(rc.Name)
+
+	n, err := dirs.nextJob(dir)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Create(filepath.Join(dir, fmt.Sprintf("%d_%s.txt", n, name)))
+	if err != nil {
+		return nil, err
+	}
+
+	logFiles := &jobLogFiles{
+		dir:    filepath.Join(dir, name),
+		masker: valueMasker(rc.Config.InsecureSecrets, rc.Config.Secrets),
+		file:   file,
+		steps:  map[string]*os.File{},
+	}
+	logFiles.remove = addJobHook(ctx, logFiles)
+	return logFiles, nil
+}
+
+func (l *jobLogFiles) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (l *jobLogFiles) Fire(entry *logrus.Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+
+	message := ansiEscape.ReplaceAllString(strings.TrimSuffix(l.masker(entry).Message, "\n"), "")
+	timestamp := entry.Time.UTC().Format("2006-01-02T15:04:05.0000000Z07:00")
+	var b strings.Builder
+	for _, line := range strings.Split(message, "\n") {
+		fmt.Fprintf(&b, "%s %s\n", timestamp, strings.TrimSuffix(line, "\r"))
+	}
+
+	if _, err := l.file.WriteString(b.String()); err != nil {
+		return err
+	}
+	if step := l.step(entry); step != nil {
+		if _, err := step.WriteString(b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// step returns the file of the stage of the step of an entry, nil if the entry does not belong to a step
+func (l *jobLogFiles) step(entry *logrus.Entry) *os.File {
+	name, ok := entry.Data["step"].(string)
+	if !ok {
+		return nil
+	}
+	name, _, _ = strings.Cut(name, "\n")
+	key := name
+	if stepID, ok := entry.Data["stepID"].([]string); ok && len(stepID) > 0 {
+		key = stepID[0]
+	}
+	stage, _ := entry.Data["stage"].(string)
+	if stage == stepStagePre.String() || stage == stepStagePost.String() {
+		name = fmt.Sprintf("%s %s", stage, name)
+	}
+	key = stage + "/" + key
+
+	if file, ok := l.steps[key]; ok {
+		return file
+	}
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return nil
+	}
+	file, err := os.Create(filepath.Join(l.dir, fmt.Sprintf("%d_%s.txt", len(l.steps)+1, logFileName(name))))
+	if err != nil {
+		return nil
+	}
+	l.steps[key] = file
+	return file
+}
+
+// close closes the files once the job completed
+func (l *jobLogFiles) close() {
+	l.remove()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return
+	}
+	_ = l.file.Close()
+	l.file = nil
+	for _, file := range l.steps {
+		_ = file.Close()
+	}
+}
+
+// logFileName replaces the characters which are not valid in file names, and the names which refer to a directory
+// itself or its parent
+func logFileName(name string) string {
+	name = strings.TrimSpace(invalidFileChars.ReplaceAllString(name, "_"))
+	if runes := []rune(name); len(runes) > maxLogFileNameLength {
+		name = string(runes[:maxLogFileNameLength])
+	}
+	if name == "" || name == "." || name == ".." {
+		return "_" + name
+	}
+	return name
+}
@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFileName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"build":      "build",
+		"a/b: c":     "a_b_ c",
+		"":           "_",
+		".":          "_.",
+		"..":         "_..",
+		"  ..  ":     "_..",
+		"..release":  "..release",
+		"../../etc":  ".._.._etc",
+		"deploy\x00": "deploy_",
+	} {
+		assert.Equal(t, expected, logFileName(name), name)
+	}
+}
+
+func TestLogDirsWorkflowDir(t *testing.T) {
+	logDir := t.TempDir()
+	dirs := newLogDirs()
+
+	for _, tt := range []struct {
+		name, file, dir string
+	}{
+		{"build", "build.yml", "build"},
+		{"build", "build.yml", "build"},
+		{"build", "release.yml", "release.yml"},
+		{"..", "up.yml", "_.."},
+		{".", "dot.yml", "_."},
+		{"build", "build.yml", "build"},
+	} {
+		dir, err := dirs.workflowDir(logDir, tt.name, tt.file)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(logDir, tt.dir), dir, "%s (%s)", tt.name, tt.file)
+	}
+}
+
+func TestLogDirsNextJob(t *testing.T) {
+	logDir := t.TempDir()
+	dir := filepath.Join(logDir, "build")
+	// the logs of a previous run and files which are not logs of jobs
+	for name, content := range map[string]string{
+		"1_test.txt":            "job",
+		"test/1_Set up job.txt": "step",
+		"2_lint.txt":            "job",
+		"lint/notes.md":         "kept",
+		"notes.md":              "kept",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(logDir, "keep.txt"), []byte("kept"), 0o600))
+
+	dirs := newLogDirs()
+	n, err := dirs.nextJob(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1_test.txt"), []byte("job"), 0o600))
+
+	// the files of the jobs of this run are kept
+	n, err = dirs.nextJob(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	files := []string{}
+	require.NoError(t, filepath.WalkDir(logDir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(logDir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	}))
+	assert.ElementsMatch(t, []string{"keep.txt", "build/1_test.txt", "build/lint/notes.md", "build/notes.md"}, files)
+}
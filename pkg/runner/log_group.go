@@ -0,0 +1,133 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+// groupFormatter tracks the groups opened by ::group:: and closed by ::endgroup:: in the log of each step,
+// the entries in a group get the fields group (title of the innermost group) and groupDepth.
+// If collapse is set the output of a group is held back until it ends and replaced by a single line
+// unless an error was logged in the group, the held back output is written out if the step fails.
+type groupFormatter struct {
+	logrus.Formatter
+	collapse bool
+	steps    map[string]*stepGroups
+}
+
+// stepGroups are the groups of a stage of a step
+type stepGroups struct {
+	open      []*logGroup
+	collapsed bytes.Buffer // output of the collapsed groups of the step
+}
+
+type logGroup struct {
+	title  string
+	output bytes.Buffer // held back output of a group, including the line which started it
+	lines  int
+	failed bool
+}
+
+func (f *groupFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	stepID, ok := entry.Data["stepID"]
+	if !ok {
+		return f.Formatter.Format(entry)
+	}
+	key := fmt.Sprint(stepID, entry.Data["stage"])
+	if f.steps == nil {
+		f.steps = map[string]*stepGroups{}
+	}
+	groups, ok := f.steps[key]
+	if !ok {
+		groups = &stepGroups{}
+		f.steps[key] = groups
+	}
+
+	if _, ok := entry.Data["stepResult"]; ok {
+		delete(f.steps, key)
+		return f.endStep(entry, groups)
+	}
+
+	switch entry.Data["command"] {
+	case "group":
+		title, _ := entry.Data["arg"].(string)
+		groups.open = append(groups.open, &logGroup{title: title})
+		entry.Data["group"] = title
+		entry.Data["groupDepth"] = len(groups.open)
+	case "endgroup":
+		if len(groups.open) == 0 {
+			break
+		}
+		group := groups.open[len(groups.open)-1]
+		entry.Data["group"] = group.title
+		entry.Data["groupDepth"] = len(groups.open)
+		entry.Message = fmt.Sprintf("  \U000025B2  %s", group.title)
+		groups.open = groups.open[:len(groups.open)-1]
+		if len(groups.open) == 0 && f.collapse && !group.failed {
+			return f.collapseGroup(entry, groups, group)
+		}
+	default:
+		if len(groups.open) > 0 {
+			entry.Data["group"] = groups.open[len(groups.open)-1].title
+			entry.Data["groupDepth"] = len(groups.open)
+		}
+	}
+
+	b, err := f.Formatter.Format(entry)
+	if err != nil || !f.collapse {
+		return b, err
+	}
+	// the output of a top level group is held back, until an error is logged in it
+	top := groups.top()
+	if top == nil || top.failed {
+		return b, nil
+	}
+	top.output.Write(b)
+	top.lines++
+	if entry.Level <= logrus.ErrorLevel {
+		top.failed = true
+		return top.output.Bytes(), nil
+	}
+	return nil, nil
+}
+
+// top returns the open top level group, or the group which just ended
+func (g *stepGroups) top() *logGroup {
+	if len(g.open) == 0 {
+		return nil
+	}
+	return g.open[0]
+}
+
+// collapseGroup replaces the output of a successful group by a line with its title and the number of its lines
+func (f *groupFormatter) collapseGroup(entry *logrus.Entry, groups *stepGroups, group *logGroup) ([]byte, error) {
+	end, err := f.Formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	group.output.Write(end)
+	groups.collapsed.Write(group.output.Bytes())
+
+	entry.Message = fmt.Sprintf("  \U000025B6  %s (%d lines collapsed)", group.title, group.lines-1)
+	return f.Formatter.Format(entry)
+}
+
+// endStep writes out the output of the groups which are not ended and, if the step failed, of the collapsed groups
+func (f *groupFormatter) endStep(entry *logrus.Entry, groups *stepGroups) ([]byte, error) {
+	out := &bytes.Buffer{}
+	if fmt.Sprint(entry.Data["stepResult"]) == model.StepStatusFailure.String() {
+		out.Write(groups.collapsed.Bytes())
+	}
+	if top := groups.top(); f.collapse && top != nil && !top.failed {
+		out.Write(top.output.Bytes())
+	}
+	b, err := f.Formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	out.Write(b)
+	return out.Bytes(), nil
+}
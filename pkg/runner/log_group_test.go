@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+// runGroupedStep writes the output of a step to the log of a job as useStepLogger does and returns the log
+func runGroupedStep(t *testing.T, formatter logrus.Formatter, collapse bool, output string, result fmt.Stringer) string {
+	t.Setenv("CLICOLOR_FORCE", "0")
+	out := &bytes.Buffer{}
+	logger := logrus.New()
+	logger.SetOutput(out)
+	logger.SetFormatter(&groupFormatter{Formatter: formatter, collapse: collapse})
+	ctx := common.WithLogger(context.Background(), logger.WithField("job", "test"))
+	ctx = withStepLogger(ctx, "build", "Build", "Main")
+
+	rc := &RunContext{Config: &Config{LogOutput: true}}
+	rawLogger := common.Logger(ctx).WithField("raw_output", true)
+	writer := common.NewLineWriter(rc.commandHandler(ctx), func(s string) bool {
+		rawLogger.Infof("%s", s)
+		return true
+	})
+	_, _ = writer.Write([]byte(output))
+	common.Logger(ctx).WithField("stepResult", result).Infof("  %s", result)
+	return out.String()
+}
+
+const groupedOutput = `before
+::group::Install
+installing
+::group::Dependencies
+a
+::endgroup::
+::endgroup::
+after
+`
+
+func TestLogGroups(t *testing.T) {
+	assert.Equal(t, `[test]   | before
+[test]   ▼  Install
+[test]   |   installing
+[test]     ▼  Dependencies
+[test]   |     a
+[test]     ▲  Dependencies
+[test]   ▲  Install
+[test]   | after
+[test]   success
+`, runGroupedStep(t, &jobLogFormatter{}, false, groupedOutput, model.StepStatusSuccess))
+}
+
+func TestLogGroupsJSON(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(runGroupedStep(t, &logrus.JSONFormatter{}, false, groupedOutput, model.StepStatusSuccess)), "\n")
+	fields := []map[string]interface{}{}
+	for _, line := range lines {
+		entry := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		fields = append(fields, map[string]interface{}{"msg": entry["msg"], "group": entry["group"], "groupDepth": entry["groupDepth"], "command": entry["command"]})
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"msg": "before\n", "group": nil, "groupDepth": nil, "command": nil},
+		{"msg": "  ▼  Install", "group": "Install", "groupDepth": 1.0, "command": "group"},
+		{"msg": "installing\n", "group": "Install", "groupDepth": 1.0, "command": nil},
+		{"msg": "  ▼  Dependencies", "group": "Dependencies", "groupDepth": 2.0, "command": "group"},
+		{"msg": "a\n", "group": "Dependencies", "groupDepth": 2.0, "command": nil},
+		{"msg": "  ▲  Dependencies", "group": "Dependencies", "groupDepth": 2.0, "command": "endgroup"},
+		{"msg": "  ▲  Install", "group": "Install", "groupDepth": 1.0, "command": "endgroup"},
+		{"msg": "after\n", "group": nil, "groupDepth": nil, "command": nil},
+		{"msg": "  success", "group": nil, "groupDepth": nil, "command": nil},
+	}, fields)
+}
+
+func TestLogGroupsCollapse(t *testing.T) {
+	table := []struct {
+		name   string
+		output string
+		result fmt.Stringer
+		log    string
+	}{
+		{
+			name:   "successful",
+			output: groupedOutput,
+			result: model.StepStatusSuccess,
+			log: `[test]   | before
+[test]   ▶  Install (4 lines collapsed)
+[test]   | after
+[test]   success
+`,
+		},
+		{
+			name:   "failed step",
+			output: groupedOutput,
+			result: model.StepStatusFailure,
+			log: `[test]   | before
+[test]   ▶  Install (4 lines collapsed)
+[test]   | after
+[test]   ▼  Install
+[test]   |   installing
+[test]     ▼  Dependencies
+[test]   |     a
+[test]     ▲  Dependencies
+[test]   ▲  Install
+[test]   failure
+`,
+		},
+		{
+			name:   "error in group",
+			output: "::group::Test\nok\n::error::broken\nmore\n::endgroup::\n",
+			result: model.StepStatusSuccess,
+			log: `[test]   ▼  Test
+[test]   |   ok
+[test]     ❗  ::error::broken
+[test]   |   more
+[test]   ▲  Test
+[test]   success
+`,
+		},
+		{
+			name:   "group not ended",
+			output: "::group::Test\nok\n",
+			result: model.StepStatusSuccess,
+			log: `[test]   ▼  Test
+[test]   |   ok
+[test]   success
+`,
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.log, runGroupedStep(t, &jobLogFormatter{}, true, tt.output, tt.result))
+		})
+	}
+}
@@ -3,9 +3,11 @@ package runner
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 
@@ -96,10 +98,16 @@ func WithJobLogger(ctx context.Context, jobID string, jobName string, config *Co
 		logger.SetFormatter(formatter)
 	}
 
-	logger.SetFormatter(&maskedFormatter{
-		Formatter: logger.Formatter,
-		masker:    valueMasker(config.InsecureSecrets, config.Secrets),
+	logger.SetFormatter(&groupFormatter{
+		Formatter: &maskedFormatter{
+			Formatter: logger.Formatter,
+			masker:    valueMasker(config.InsecureSecrets, config.Secrets),
+		},
+		collapse: config.CollapseGroups,
 	})
+	hooks := &jobHooks{job: jobName}
+	logger.AddHook(hooks)
+	ctx = context.WithValue(ctx, jobHooksContextKeyVal, hooks)
 	rtn := logger.WithFields(logrus.Fields{
 		"job":    jobName,
 		"jobID":  jobID,
@@ -110,6 +118,55 @@ func WithJobLogger(ctx context.Context, jobID string, jobName string, config *Co
 	return common.WithLogger(ctx, rtn)
 }
 
+type jobHooksContextKey string
+
+const jobHooksContextKeyVal = jobHooksContextKey("jobhooks")
+
+// jobHooks is the hook of a job logger which passes the entries of the job to the hooks of the job, e.g. its recorder.
+// The logger is shared by all jobs if it comes from a JobLoggerFactory, so the hooks of a job are added to the jobHooks
+// of the job instead of the logger, which can not remove them once the job finished.
+type jobHooks struct {
+	mu    sync.RWMutex
+	job   string
+	hooks []logrus.Hook
+}
+
+func (h *jobHooks) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *jobHooks) Fire(entry *logrus.Entry) error {
+	if entry.Data["job"] != h.job {
+		return nil
+	}
+	h.mu.RLock()
+	hooks := h.hooks
+	h.mu.RUnlock()
+	var errs []error
+	for _, hook := range hooks {
+		if slices.Contains(hook.Levels(), entry.Level) {
+			errs = append(errs, hook.Fire(entry))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// addJobHook adds a hook for the entries of the job of the logger of the context, remove removes it once the job finished
+func addJobHook(ctx context.Context, hook logrus.Hook) (remove func()) {
+	hooks, ok := ctx.Value(jobHooksContextKeyVal).(*jobHooks)
+	if !ok {
+		return func() {}
+	}
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.hooks = append(slices.Clone(hooks.hooks), hook)
+	return func() {
+		hooks.mu.Lock()
+		defer hooks.mu.Unlock()
+		hooks.hooks = slices.DeleteFunc(slices.Clone(hooks.hooks), func(h logrus.Hook) bool { return h == hook })
+	}
+}
+
 func WithCompositeLogger(ctx context.Context, masks *[]string) context.Context {
 	ctx = WithMasks(ctx, masks)
 	return common.WithLogger(ctx, common.Logger(ctx).WithFields(logrus.Fields{}).WithContext(ctx))
@@ -213,12 +270,13 @@ func (f *jobLogFormatter) printColored(b *bytes.Buffer, entry *logrus.Entry) {
 		debugFlag = "[DEBUG] "
 	}
 
+	indent := groupIndent(entry)
 	if entry.Data["raw_output"] == true {
-		fmt.Fprintf(b, "\x1b[%dm|\x1b[0m %s", f.color, entry.Message)
+		fmt.Fprintf(b, "\x1b[%dm|\x1b[0m %s%s", f.color, indent, entry.Message)
 	} else if entry.Data["dryrun"] == true {
-		fmt.Fprintf(b, "\x1b[1m\x1b[%dm\x1b[7m*DRYRUN*\x1b[0m \x1b[%dm[%s] \x1b[0m%s%s", gray, f.color, job, debugFlag, entry.Message)
+		fmt.Fprintf(b, "\x1b[1m\x1b[%dm\x1b[7m*DRYRUN*\x1b[0m \x1b[%dm[%s] \x1b[0m%s%s%s", gray, f.color, job, indent, debugFlag, entry.Message)
 	} else {
-		fmt.Fprintf(b, "\x1b[%dm[%s] \x1b[0m%s%s", f.color, job, debugFlag, entry.Message)
+		fmt.Fprintf(b, "\x1b[%dm[%s] \x1b[0m%s%s%s", f.color, job, indent, debugFlag, entry.Message)
 	}
 }
 
@@ -237,13 +295,23 @@ func (f *jobLogFormatter) print(b *bytes.Buffer, entry *logrus.Entry) {
 		debugFlag = "[DEBUG] "
 	}
 
+	indent := groupIndent(entry)
 	if entry.Data["raw_output"] == true {
-		fmt.Fprintf(b, "[%s]   | %s", job, entry.Message)
+		fmt.Fprintf(b, "[%s]   | %s%s", job, indent, entry.Message)
 	} else if entry.Data["dryrun"] == true {
-		fmt.Fprintf(b, "*DRYRUN* [%s] %s%s", job, debugFlag, entry.Message)
+		fmt.Fprintf(b, "*DRYRUN* [%s] %s%s%s", job, indent, debugFlag, entry.Message)
 	} else {
-		fmt.Fprintf(b, "[%s] %s%s", job, debugFlag, entry.Message)
+		fmt.Fprintf(b, "[%s] %s%s%s", job, indent, debugFlag, entry.Message)
+	}
+}
+
+// groupIndent indents the output in a group of the log of a step, the lines which start and end a group are not indented by it
+func groupIndent(entry *logrus.Entry) string {
+	depth, _ := entry.Data["groupDepth"].(int)
+	if command := entry.Data["command"]; command == "group" || command == "endgroup" {
+		depth--
 	}
+	return strings.Repeat("  ", max(depth, 0))
 }
 
 func (f *jobLogFormatter) isColored(entry *logrus.Entry) bool {
@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+)
+
+type sharedJobLoggerFactory struct {
+	logger *logrus.Logger
+}
+
+func (factory *sharedJobLoggerFactory) WithJobLogger() *logrus.Logger {
+	return factory.logger
+}
+
+type messageHook struct {
+	messages []string
+}
+
+func (h *messageHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *messageHook) Fire(entry *logrus.Entry) error {
+	h.messages = append(h.messages, entry.Message)
+	return nil
+}
+
+func TestAddJobHook(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := WithJobLoggerFactory(context.Background(), &sharedJobLoggerFactory{logger})
+	buildCtx := WithJobLogger(ctx, "build", "build", &Config{}, &[]string{}, nil)
+	testCtx := WithJobLogger(ctx, "test", "test", &Config{}, &[]string{}, nil)
+
+	hook := &messageHook{}
+	remove := addJobHook(buildCtx, hook)
+	common.Logger(buildCtx).Info("building")
+	common.Logger(testCtx).Info("testing")
+	remove()
+	common.Logger(buildCtx).Info("built")
+
+	assert.Equal(t, []string{"building"}, hook.messages, "the hook gets the entries of its job until it is removed")
+}
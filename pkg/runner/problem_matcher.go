@@ -0,0 +1,277 @@
+package runner
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/sirupsen/logrus"
+)
+
+// problemMatcherFile is the file registered with the add-matcher command
+type problemMatcherFile struct {
+	ProblemMatcher []*problemMatcher `json:"problemMatcher"`
+}
+
+// problemMatcher turns lines of the output of the steps into annotations
+// see https://github.com/actions/toolkit/blob/main/docs/problem-matchers.md
+type problemMatcher struct {
+	Owner    string            `json:"owner"`
+	Severity string            `json:"severity"`
+	Pattern  []*problemPattern `json:"pattern"`
+
+	// index of the pattern matching the next line and the values captured by the previous lines of a multi-line match
+	next    int
+	partial problem
+}
+
+type problemPattern struct {
+	Regexp   string `json:"regexp"`
+	File     int    `json:"file"`
+	FromPath int    `json:"fromPath"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity int    `json:"severity"`
+	Code     int    `json:"code"`
+	Message  int    `json:"message"`
+	Loop     bool   `json:"loop"`
+
+	re *regexp.Regexp
+}
+
+// problem is an annotation found by a problem matcher
+type problem struct {
+	Severity string
+	File     string
+	FromPath string
+	Line     string
+	Column   string
+	Code     string
+	Message  string
+}
+
+func parseProblemMatchers(content []byte) ([]*problemMatcher, error) {
+	file := problemMatcherFile{}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	for _, matcher := range file.ProblemMatcher {
+		if matcher.Owner == "" {
+			return nil, fmt.Errorf("problem matcher without an owner")
+		}
+		if len(matcher.Pattern) == 0 {
+			return nil, fmt.Errorf("problem matcher '%s' without patterns", matcher.Owner)
+		}
+		for i, pattern := range matcher.Pattern {
+			re, err := regexp.Compile(pattern.Regexp)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern of problem matcher '%s': %w", matcher.Owner, err)
+			}
+			if pattern.Loop && i != len(matcher.Pattern)-1 {
+				return nil, fmt.Errorf("only the last pattern of problem matcher '%s' can loop", matcher.Owner)
+			}
+			pattern.re = re
+		}
+		if matcher.Pattern[len(matcher.Pattern)-1].Message == 0 {
+			return nil, fmt.Errorf("the last pattern of problem matcher '%s' has no message", matcher.Owner)
+		}
+	}
+	return file.ProblemMatcher, nil
+}
+
+// match consumes a line of the output and returns the problem it completes, if any
+func (m *problemMatcher) match(line string) (problem, bool) {
+	if m.next > 0 {
+		if groups := m.Pattern[m.next].re.FindStringSubmatch(line); groups != nil {
+			return m.advance(m.next, groups)
+		}
+		// the multi-line match is broken, the line may start a new one
+		m.reset()
+	}
+	if groups := m.Pattern[0].re.FindStringSubmatch(line); groups != nil {
+		return m.advance(0, groups)
+	}
+	return problem{}, false
+}
+
+// advance captures the values of a matched pattern, it returns the problem once the last pattern matched
+func (m *problemMatcher) advance(i int, groups []string) (problem, bool) {
+	pattern := m.Pattern[i]
+	p := m.partial
+	pattern.capture(groups, &p)
+	if i < len(m.Pattern)-1 {
+		m.partial = p
+		m.next = i + 1
+		return problem{}, false
+	}
+
+	if pattern.Loop && i > 0 {
+		// the next lines matched by the looping pattern are problems sharing the values of the previous lines
+		m.next = i
+	} else {
+		m.reset()
+	}
+	if p.Severity == "" {
+		p.Severity = m.Severity
+	}
+	p.Severity = normalizeSeverity(p.Severity)
+	return p, true
+}
+
+func (m *problemMatcher) reset() {
+	m.next = 0
+	m.partial = problem{}
+}
+
+func (pattern *problemPattern) capture(groups []string, p *problem) {
+	group := func(i int, value *string) {
+		if i > 0 && i < len(groups) && groups[i] != "" {
+			*value = strings.TrimSpace(groups[i])
+		}
+	}
+	group(pattern.File, &p.File)
+	group(pattern.FromPath, &p.FromPath)
+	group(pattern.Line, &p.Line)
+	group(pattern.Column, &p.Column)
+	group(pattern.Severity, &p.Severity)
+	group(pattern.Code, &p.Code)
+	group(pattern.Message, &p.Message)
+}
+
+func normalizeSeverity(severity string) string {
+	severity = strings.ToLower(severity)
+	switch {
+	case strings.HasPrefix(severity, "warn"):
+		return "warning"
+	case strings.HasPrefix(severity, "notice"), strings.HasPrefix(severity, "info"):
+		return "notice"
+	default:
+		return "error"
+	}
+}
+
+// jobRunContext returns the RunContext of the job, composite actions share the problem matchers of the job
+func (rc *RunContext) jobRunContext() *RunContext {
+	for rc.Parent != nil {
+		rc = rc.Parent
+	}
+	return rc
+}
+
+// addProblemMatchers loads the problem matchers of a file in the job container,
+// matchers of an owner which is already registered replace the previous ones
+func (rc *RunContext) addProblemMatchers(ctx context.Context, file string) error {
+	if !path.IsAbs(file) && !strings.Contains(file, ":") {
+		file = path.Join(rc.JobContainer.ToContainerPath(rc.Config.Workdir), file)
+	}
+	archive, err := rc.JobContainer.GetContainerArchive(ctx, file)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	reader := tar.NewReader(archive)
+	if _, err := reader.Next(); err != nil {
+		return err
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	matchers, err := parseProblemMatchers(content)
+	if err != nil {
+		return fmt.Errorf("invalid problem matcher file '%s': %w", file, err)
+	}
+
+	jobRC := rc.jobRunContext()
+	for _, matcher := range matchers {
+		jobRC.removeProblemMatcher(matcher.Owner)
+		jobRC.problemMatchers = append(jobRC.problemMatchers, matcher)
+	}
+	return nil
+}
+
+func (rc *RunContext) removeProblemMatcher(owner string) {
+	jobRC := rc.jobRunContext()
+	matchers := jobRC.problemMatchers[:0]
+	for _, matcher := range jobRC.problemMatchers {
+		if matcher.Owner != owner {
+			matchers = append(matchers, matcher)
+		}
+	}
+	jobRC.problemMatchers = matchers
+}
+
+// matchProblems applies the problem matchers of the job to a line of the output and logs the problems as annotations
+func (rc *RunContext) matchProblems(ctx context.Context, line string) {
+	matchers := rc.jobRunContext().problemMatchers
+	if len(matchers) == 0 {
+		return
+	}
+	line = strings.TrimRight(line, "\r\n")
+	for _, matcher := range matchers {
+		p, ok := matcher.match(line)
+		if !ok {
+			continue
+		}
+		kvPairs := map[string]string{}
+		if p.File != "" {
+			kvPairs["file"] = rc.problemFile(ctx, p)
+		}
+		if _, err := strconv.Atoi(p.Line); err == nil {
+			kvPairs["line"] = p.Line
+		}
+		if _, err := strconv.Atoi(p.Column); err == nil {
+			kvPairs["col"] = p.Column
+		}
+		if p.Code != "" {
+			kvPairs["title"] = p.Code
+		}
+		logAnnotation(common.Logger(ctx), p.Severity, kvPairs, p.Message, line)
+		// the first matcher matching a line wins, like on GitHub
+		return
+	}
+}
+
+// problemFile returns the file of a problem relative to the workspace
+func (rc *RunContext) problemFile(ctx context.Context, p problem) string {
+	file := p.File
+	if p.FromPath != "" && !path.IsAbs(file) {
+		file = path.Join(path.Dir(p.FromPath), file)
+	}
+	workspace := rc.JobContainer.ToContainerPath(rc.Config.Workdir)
+	if rel, ok := strings.CutPrefix(file, strings.TrimSuffix(workspace, "/")+"/"); ok {
+		return rel
+	}
+	common.Logger(ctx).Debugf("file '%s' of the problem is not in the workspace", file)
+	return file
+}
+
+// logAnnotation logs an annotation with the fields of the workflow commands error, warning and notice
+func logAnnotation(logger logrus.FieldLogger, severity string, kvPairs map[string]string, message string, raw string) {
+	entry := logger.WithFields(logrus.Fields{"command": severity, "kvPairs": kvPairs, "arg": message, "raw": raw})
+	location := kvPairs["file"]
+	for _, key := range []string{"line", "col"} {
+		if kvPairs[key] != "" {
+			location += ":" + kvPairs[key]
+		}
+	}
+	if location != "" {
+		message = location + ": " + message
+	}
+	switch severity {
+	case "warning":
+		entry.Warnf("  \U0001F6A7  %s", message)
+	case "notice":
+		entry.Infof("  \U0001F4DD  %s", message)
+	default:
+		entry.Errorf("  \U00002757  %s", message)
+	}
+}
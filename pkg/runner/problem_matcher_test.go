@@ -0,0 +1,130 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+)
+
+const gccMatcher = `{
+  "problemMatcher": [{
+    "owner": "gcc",
+    "pattern": [{
+      "regexp": "^(.+):(\\d+):(\\d+):\\s+(warning|error):\\s+(.+)$",
+      "file": 1, "line": 2, "column": 3, "severity": 4, "message": 5
+    }]
+  }]
+}`
+
+const eslintMatcher = `{
+  "problemMatcher": [{
+    "owner": "eslint-stylish",
+    "severity": "warning",
+    "pattern": [
+      {"regexp": "^([^\\s].*)$", "file": 1},
+      {"regexp": "^\\s+(\\d+):(\\d+)\\s+(error|warning|info)?\\s*(.*?)\\s\\s+(\\S+)$", "line": 1, "column": 2, "severity": 3, "message": 4, "code": 5, "loop": true}
+    ]
+  }]
+}`
+
+func TestProblemMatcherMatch(t *testing.T) {
+	tables := []struct {
+		name     string
+		matcher  string
+		lines    []string
+		problems []problem
+	}{
+		{"single-line", gccMatcher, []string{
+			"compiling",
+			"main.c:4:12: error: expected ';'",
+			"util.c:10:1: warning: unused variable",
+		}, []problem{
+			{Severity: "error", File: "main.c", Line: "4", Column: "12", Message: "expected ';'"},
+			{Severity: "warning", File: "util.c", Line: "10", Column: "1", Message: "unused variable"},
+		}},
+		{"loop", eslintMatcher, []string{
+			"src/a.js",
+			"  1:10  error  'x' is unused  no-unused-vars",
+			"  3:1  Missing semicolon  semi",
+			"",
+			"src/b.js",
+			"  7:2  info  Prefer const  prefer-const",
+		}, []problem{
+			{Severity: "error", File: "src/a.js", Line: "1", Column: "10", Code: "no-unused-vars", Message: "'x' is unused"},
+			{Severity: "warning", File: "src/a.js", Line: "3", Column: "1", Code: "semi", Message: "Missing semicolon"},
+			{Severity: "notice", File: "src/b.js", Line: "7", Column: "2", Code: "prefer-const", Message: "Prefer const"},
+		}},
+		{"broken", eslintMatcher, []string{
+			"src/a.js",
+			"src/b.js",
+			"  2:4  error  Unexpected var  no-var",
+		}, []problem{
+			{Severity: "error", File: "src/b.js", Line: "2", Column: "4", Code: "no-var", Message: "Unexpected var"},
+		}},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			matchers, err := parseProblemMatchers([]byte(table.matcher))
+			require.NoError(t, err)
+			problems := []problem{}
+			for _, line := range table.lines {
+				if p, ok := matchers[0].match(line); ok {
+					problems = append(problems, p)
+				}
+			}
+			assert.Equal(t, table.problems, problems)
+		})
+	}
+}
+
+func TestParseProblemMatchersInvalid(t *testing.T) {
+	for _, content := range []string{
+		`{"problemMatcher": [{"pattern": [{"regexp": "(.*)", "message": 1}]}]}`,
+		`{"problemMatcher": [{"owner": "a", "pattern": [{"regexp": "(", "message": 1}]}]}`,
+		`{"problemMatcher": [{"owner": "a", "pattern": [{"regexp": "(.*)", "loop": true}, {"regexp": "(.*)", "message": 1}]}]}`,
+		`{"problemMatcher": [{"owner": "a", "pattern": [{"regexp": "(.*)", "file": 1}]}]}`,
+	} {
+		_, err := parseProblemMatchers([]byte(content))
+		assert.Error(t, err, content)
+	}
+}
+
+func TestAddMatcherCommand(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "gcc.json"), []byte(gccMatcher), 0o600))
+
+	logger, hook := test.NewNullLogger()
+	ctx := common.WithLogger(context.Background(), logger)
+	rc := &RunContext{
+		Config:       &Config{Workdir: workspace},
+		JobContainer: &container.HostEnvironment{Path: workspace, Workdir: workspace},
+	}
+	composite := &RunContext{Config: rc.Config, JobContainer: rc.JobContainer, Parent: rc}
+
+	// matchers added by a composite action apply to the whole job
+	composite.commandHandler(ctx)("::add-matcher::gcc.json\n")
+	handler := rc.commandHandler(ctx)
+	handler(filepath.Join(workspace, "main.c") + ":4:12: error: expected ';'\n")
+
+	entry := hook.LastEntry()
+	assert.Equal(t, logrus.ErrorLevel, entry.Level)
+	assert.Equal(t, "error", entry.Data["command"])
+	assert.Equal(t, map[string]string{"file": "main.c", "line": "4", "col": "12"}, entry.Data["kvPairs"])
+	assert.Equal(t, "expected ';'", entry.Data["arg"])
+	assert.Equal(t, "  \U00002757  main.c:4:12: expected ';'", entry.Message)
+
+	handler("::remove-matcher owner=gcc::\n")
+	hook.Reset()
+	handler("main.c:4:12: error: expected ';'\n")
+	assert.Empty(t, hook.AllEntries())
+}
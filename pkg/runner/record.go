@@ -0,0 +1,270 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+// JobRecorder receives the record of every job of a run once the job completed
+type JobRecorder func(record JobRecord)
+
+// JobRecord is the outcome of a job of a run, a job with a matrix has one record per combination
+type JobRecord struct {
+	Workflow       string                 `json:"workflow"` // file of the workflow
+	WorkflowName   string                 `json:"workflowName"`
+	JobID          string                 `json:"jobID"`
+	Name           string                 `json:"name"`
+	Matrix         map[string]interface{} `json:"matrix,omitempty"`
+	Result         string                 `json:"result"`
+	Outputs        map[string]string      `json:"outputs,omitempty"`
+	Environment    string                 `json:"environment,omitempty"`    // name of the deployment environment
+	EnvironmentURL string                 `json:"environmentUrl,omitempty"` // evaluated url of the deployment environment
+	Steps          []StepRecord           `json:"steps,omitempty"`
+	Annotations    []Annotation           `json:"annotations,omitempty"`
+	Log            string                 `json:"log,omitempty"`
+	StartedAt      time.Time              `json:"startedAt"`
+	FinishedAt     time.Time              `json:"finishedAt"`
+	Reused         bool                   `json:"reused,omitempty"` // the result of a previous run was reused instead of running the job
+}
+
+// StepRecord is the outcome of a step of a job
+type StepRecord struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Outcome    string            `json:"outcome"`
+	Conclusion string            `json:"conclusion"`
+	Outputs    map[string]string `json:"outputs,omitempty"`
+	Duration   time.Duration     `json:"duration,omitempty"` // execution time of the main stage
+	Error      string            `json:"error,omitempty"`    // error of the failed step
+	Summary    string            `json:"summary,omitempty"`  // markdown written to GITHUB_STEP_SUMMARY
+	Log        string            `json:"-"`                  // log of the step, the history keeps it as part of the log of the job
+}
+
+// Annotation is an error, warning or notice reported by a step with a workflow command or a problem matcher
+type Annotation struct {
+	Level     string `json:"level"` // error, warning or notice
+	Message   string `json:"message"`
+	Title     string `json:"title,omitempty"`
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	Column    int    `json:"column,omitempty"`
+	EndColumn int    `json:"endColumn,omitempty"`
+	StepID    string `json:"stepID,omitempty"`
+	Step      string `json:"step,omitempty"` // name of the step
+}
+
+// newAnnotation returns the annotation of a log entry of the commands error, warning and notice
+func newAnnotation(entry *logrus.Entry, masker entryProcessor) (Annotation, bool) {
+	level, _ := entry.Data["command"].(string)
+	if level != "error" && level != "warning" && level != "notice" {
+		return Annotation{}, false
+	}
+	arg, _ := entry.Data["arg"].(string)
+	kvPairs, _ := entry.Data["kvPairs"].(map[string]string)
+	// the properties of the command may contain secrets as well as its message
+	mask := func(value string) string {
+		if value == "" {
+			return value
+		}
+		return masker(&logrus.Entry{Context: entry.Context, Message: value}).Message
+	}
+	annotation := Annotation{
+		Level:   level,
+		Message: mask(arg),
+		Title:   mask(kvPairs["title"]),
+		File:    mask(kvPairs["file"]),
+	}
+	for key, value := range map[string]*int{"line": &annotation.Line, "endLine": &annotation.EndLine, "col": &annotation.Column, "endColumn": &annotation.EndColumn} {
+		*value, _ = strconv.Atoi(kvPairs[key])
+	}
+	if stepID, ok := entry.Data["stepID"].([]string); ok && len(stepID) > 0 {
+		annotation.StepID = stepID[0]
+	}
+	if step, ok := entry.Data["step"].(string); ok {
+		annotation.Step = step
+	}
+	return annotation, true
+}
+
+// ReusedJob is the result of a job of a previous run, which is reused instead of running the job again
+type ReusedJob struct {
+	Result  string
+	Outputs map[string]string
+}
+
+// JobKey identifies a job of a workflow across runs
+func JobKey(workflowFile string, jobID string) string {
+	return fmt.Sprintf("%s/%s", workflowFile, jobID)
+}
+
+// jobRecorder is a hook of the job logger which keeps the log and the result of a job
+type jobRecorder struct {
+	mu          sync.Mutex
+	remove      func()
+	masker      entryProcessor
+	log         strings.Builder
+	annotations []Annotation
+	steps       map[string]*stepLog
+	result      string
+	startedAt   time.Time
+}
+
+// withJobRecorder starts recording the log of the job of the run context
+func withJobRecorder(ctx context.Context, rc *RunContext) *jobRecorder {
+	recorder := &jobRecorder{
+		masker:    valueMasker(rc.Config.InsecureSecrets, rc.Config.Secrets),
+		startedAt: time.Now(),
+	}
+	recorder.remove = addJobHook(ctx, recorder)
+	return recorder
+}
+
+func (r *jobRecorder) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (r *jobRecorder) Fire(entry *logrus.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if result, ok := entry.Data["jobResult"]; ok {
+		r.result = fmt.Sprint(result)
+	}
+	if annotation, ok := newAnnotation(entry, r.masker); ok {
+		r.annotations = append(r.annotations, annotation)
+	}
+	message := strings.TrimSuffix(r.masker(entry).Message, "\n")
+	fmt.Fprintf(&r.log, "%s %s\n", entry.Time.UTC().Format(time.RFC3339), message)
+	if stepID, ok := entry.Data["stepID"].([]string); ok && len(stepID) > 0 {
+		r.step(stepID[0]).add(entry, message, r.masker)
+	}
+	return nil
+}
+
+// stepLog is the log of a step and the outcome of its main stage
+type stepLog struct {
+	log      strings.Builder
+	duration time.Duration
+	err      string
+	summary  string
+}
+
+func (r *jobRecorder) step(id string) *stepLog {
+	if r.steps == nil {
+		r.steps = map[string]*stepLog{}
+	}
+	if _, ok := r.steps[id]; !ok {
+		r.steps[id] = &stepLog{}
+	}
+	return r.steps[id]
+}
+
+func (s *stepLog) add(entry *logrus.Entry, message string, masker entryProcessor) {
+	fmt.Fprintln(&s.log, message)
+	if entry.Data["command"] == "summary" {
+		// pre and post stages can add to the summary of the step
+		content, _ := entry.Data["content"].(string)
+		s.summary += masker(&logrus.Entry{Context: entry.Context, Message: content}).Message
+	}
+	if entry.Data["stage"] != stepStageMain.String() {
+		return
+	}
+	if executionTime, ok := entry.Data["executionTime"].(time.Duration); ok {
+		s.duration = executionTime
+	}
+	// the error of the step is logged by the job executor, annotations of the step are not its error
+	if _, ok := entry.Data["command"]; !ok && entry.Level <= logrus.ErrorLevel && entry.Data["raw_output"] == nil {
+		s.err = message
+	}
+}
+
+// record stops recording and returns the record of the job once it completed
+func (r *jobRecorder) record(rc *RunContext) JobRecord {
+	r.remove()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := rc.Run.Job()
+	record := newJobRecord(rc.Run, rc.Name, rc.Matrix, r.result, job.Outputs)
+	record.Log = r.log.String()
+	record.StartedAt = r.startedAt
+	record.Annotations = r.annotations
+	record.Environment = rc.EnvironmentName
+	record.EnvironmentURL = rc.EnvironmentURL
+	for _, step := range job.Steps {
+		if step == nil {
+			continue
+		}
+		result, ok := rc.StepResults[step.ID]
+		if !ok {
+			continue
+		}
+		record.Steps = append(record.Steps, StepRecord{
+			ID:         step.ID,
+			Name:       step.String(),
+			Outcome:    result.Outcome.String(),
+			Conclusion: result.Conclusion.String(),
+			Outputs:    maps.Clone(result.Outputs),
+		})
+		if log, ok := r.steps[step.ID]; ok {
+			last := &record.Steps[len(record.Steps)-1]
+			last.Duration = log.duration
+			last.Error = log.err
+			last.Log = log.log.String()
+			last.Summary = log.summary
+		}
+	}
+	return record
+}
+
+func newJobRecord(run *model.Run, name string, matrix map[string]interface{}, result string, outputs map[string]string) JobRecord {
+	if len(matrix) == 0 {
+		matrix = nil
+	}
+	return JobRecord{
+		Workflow:     run.Workflow.File,
+		WorkflowName: run.Workflow.Name,
+		JobID:        run.JobID,
+		Name:         name,
+		Matrix:       matrix,
+		Result:       result,
+		Outputs:      maps.Clone(outputs),
+		FinishedAt:   time.Now(),
+	}
+}
+
+// reuseJob takes over the result and the outputs of the job from a previous run,
+// the outputs are available to the jobs which need it like after running the job
+func (runner *runnerImpl) reuseJob(run *model.Run, reused ReusedJob) common.Executor {
+	return func(ctx context.Context) error {
+		job := run.Job()
+		job.Result = reused.Result
+		job.Outputs = maps.Clone(reused.Outputs)
+		ctx = WithJobLogger(ctx, run.JobID, run.String(), runner.config, &[]string{}, nil)
+		common.Logger(ctx).WithField("jobResult", reused.Result).Infof("\U0001F3C1  Job %s in a previous run, reusing its result", reused.Result)
+		if runner.config.RecordJob != nil {
+			record := newJobRecord(run, run.String(), nil, reused.Result, reused.Outputs)
+			record.StartedAt = record.FinishedAt
+			record.Reused = true
+			runner.config.RecordJob(record)
+		}
+		runner.config.emit(Event{
+			Type:     EventJobFinished,
+			Workflow: run.Workflow.File,
+			JobID:    run.JobID,
+			Job:      fmt.Sprintf("%s/%s", run.Workflow.Name, run.String()),
+			Result:   reused.Result,
+			Outputs:  maps.Clone(reused.Outputs),
+			Reused:   true,
+		})
+		return nil
+	}
+}
@@ -46,13 +46,18 @@ type RunContext struct {
 	ServiceContainers   []container.ExecutionsEnvironment
 	OutputMappings      map[MappableOutput]MappableOutput
 	JobName             string
+	JobIndex            int // index of the job in its matrix, exposed as strategy.job-index
+	JobTotal            int // number of jobs in the matrix, exposed as strategy.job-total
 	ActionPath          string
 	Parent              *RunContext
 	Masks               []string
 	cleanUpJobContainer common.Executor
 	caller              *caller // job calling this RunContext (reusable workflows)
 	Cancelled           bool
+	EnvironmentName     string // evaluated name of the deployment environment of the job
+	EnvironmentURL      string // evaluated url of the deployment environment, set when the job completes
 	nodeToolFullPath    string
+	problemMatchers     []*problemMatcher // registered with add-matcher, applied to the output of the steps of the job
 }
 
 func (rc *RunContext) AddMask(mask string) {
@@ -725,7 +730,7 @@ func (rc *RunContext) Executor() (common.Executor, error) {
 			return err
 		}
 		if res {
-			return executor(ctx)
+			return rc.useEnvironment(rc.useJobConcurrency(executor))(ctx)
 		}
 		return nil
 	}, nil
@@ -790,6 +795,21 @@ func (rc *RunContext) options(ctx context.Context) string {
 	return rc.Config.ContainerOptions
 }
 
+// isContinueOnError evaluates the continue-on-error of the job, which can depend on the matrix
+func (rc *RunContext) isContinueOnError(ctx context.Context) bool {
+	expr := rc.Run.Job().RawContinueOnError
+	if len(strings.TrimSpace(expr)) == 0 {
+		return false
+	}
+
+	continueOnError, err := EvalBool(ctx, rc.NewExpressionEvaluator(ctx), expr, exprparser.DefaultStatusCheckNone)
+	if err != nil {
+		common.Logger(ctx).Errorf("  \u274C  Error in continue-on-error-expression: \"continue-on-error: %s\" (%s)", expr, err)
+		return false
+	}
+	return continueOnError
+}
+
 func (rc *RunContext) isEnabled(ctx context.Context) (bool, error) {
 	job := rc.Run.Job()
 	l := common.Logger(ctx)
@@ -874,6 +894,24 @@ func (rc *RunContext) getJobContext() *model.JobContext {
 	}
 }
 
+func (rc *RunContext) getStrategyContext() map[string]interface{} {
+	// a job without a strategy behaves like a single job matrix
+	strategy := map[string]interface{}{
+		"fail-fast":    true,
+		"job-index":    rc.JobIndex,
+		"job-total":    max(rc.JobTotal, 1),
+		"max-parallel": 1,
+	}
+	if rc.Run == nil {
+		return strategy
+	}
+	if job := rc.Run.Job(); job != nil && job.Strategy != nil {
+		strategy["fail-fast"] = job.Strategy.FailFast
+		strategy["max-parallel"] = job.Strategy.MaxParallel
+	}
+	return strategy
+}
+
 func (rc *RunContext) getStepsContext() map[string]*model.StepResult {
 	return rc.StepResults
 }
@@ -3,9 +3,11 @@ package runner
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
+	"time"
 
 	docker_container "github.com/docker/docker/api/types/container"
 	"github.com/nektos/act/pkg/common"
@@ -27,6 +29,8 @@ type Config struct {
 	BindWorkdir                        bool                         // bind the workdir to the job container
 	EventName                          string                       // name of event to run
 	EventPath                          string                       // path to JSON file to use for event.json in containers
+	EventJSON                          string                       // event payload to use if EventPath is empty, e.g. a generated one
+	ScheduleTime                       time.Time                    // simulated time of a schedule event, selects the cron expression of github.event.schedule
 	DefaultBranch                      string                       // name of the main branch for this repository
 	ReuseContainers                    bool                         // reuse containers to maintain state
 	ForcePull                          bool                         // force pulling of the image, even if already present
@@ -38,6 +42,9 @@ type Config struct {
 	Inputs                             map[string]string            // manually passed action inputs
 	Secrets                            map[string]string            // list of secrets
 	Vars                               map[string]string            // list of vars
+	EnvironmentSecrets                 map[string]map[string]string // secrets of the deployment environments, by environment name
+	EnvironmentVars                    map[string]map[string]string // vars of the deployment environments, by environment name
+	ApproveEnvironment                 EnvironmentApprover          // asked before a job deploys to an environment, nil approves all
 	Token                              string                       // GitHub token
 	InsecureSecrets                    bool                         // switch hiding output when printing to terminal
 	Platforms                          map[string]string            // list of platforms
@@ -64,6 +71,18 @@ type Config struct {
 	ContainerNetworkMode               docker_container.NetworkMode // the network mode of job containers (the value of --network)
 	ActionCache                        ActionCache                  // Use a custom ActionCache Implementation
 	ConcurrentJobs                     int                          // Number of max concurrent jobs
+	RecordJob                          JobRecorder                  // receives the record of every job once it completed, e.g. to keep a history of the runs
+	ReusedJobs                         map[string]ReusedJob         // results of jobs of a previous run by JobKey, these jobs are not run again
+	BreakOnFailure                     bool                         // pause a job when a step failed and ask the Debugger what to do
+	BreakBefore                        []string                     // steps to pause before, in the form <job>.<step-id>
+	Debugger                           StepDebugger                 // asked what to do when a job pauses at a breakpoint
+	FromStep                           string                       // skip the steps before this step, in the form [<job>.]<step-id or index>
+	ToStep                             string                       // skip the steps after this step, in the form [<job>.]<step-id or index>
+	OnlySteps                          []string                     // run only these steps, in the form [<job>.]<step-id or index>
+	CollapseGroups                     bool                         // replace the output of the ::group:: of a step by a single line unless an error was logged in it
+	Events                             EventHandler                 // receives the events of the lifecycle of the run, e.g. to show its status in an IDE
+	LogDir                             string                       // directory to write the log of every job and step to, laid out like the log archive of GitHub
+	StepOverrides                      []*StepOverride              // overrides skipping, replacing or mocking the steps matched by their uses or id, read by ReadStepOverrides
 }
 
 func (config *Config) GetConcurrentJobs() int {
@@ -107,6 +126,8 @@ func (runner *runnerImpl) configure() (Runner, error) {
 			return nil, err
 		}
 		runner.eventJSON = string(eventJSONBytes)
+	} else if runner.config.EventJSON != "" {
+		runner.eventJSON = runner.config.EventJSON
 	} else if len(runner.config.Inputs) != 0 {
 		eventMap := map[string]map[string]string{
 			"inputs": runner.config.Inputs,
@@ -122,15 +143,45 @@ func (runner *runnerImpl) configure() (Runner, error) {
 
 // NewPlanExecutor ...
 func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
+	log.Debugf("Plan Stages: %v", plan.Stages)
+
+	if err := runner.validateDispatchInputs(plan); err != nil {
+		return common.NewErrorExecutor(err)
+	}
+	// the selectors are checked against the jobs of the run, not against those of a reusable workflow it calls
+	if runner.caller == nil {
+		if err := validateStepSelectors(runner.config, plan); err != nil {
+			return common.NewErrorExecutor(err)
+		}
+	}
+
+	// cancellation contexts of the workflows with a concurrency group
+	workflowCancelCtxs := map[*model.Workflow]context.Context{}
+	newStagesExecutor := func(plan *model.Plan) common.Executor {
+		return runner.newStagesExecutor(plan, workflowCancelCtxs)
+	}
+
+	executor := usePlanEvents(runner.config, plan, runner.useWorkflowConcurrency(plan, workflowCancelCtxs, newStagesExecutor).Then(handleFailure(plan)))
+	return func(ctx context.Context) error {
+		return executor(withLogDirs(ctx))
+	}
+}
+
+// newStagesExecutor runs the stages of a plan one after another
+func (runner *runnerImpl) newStagesExecutor(plan *model.Plan, workflowCancelCtxs map[*model.Workflow]context.Context) common.Executor {
 	maxJobNameLen := 0
 
 	stagePipeline := make([]common.Executor, 0)
-	log.Debugf("Plan Stages: %v", plan.Stages)
-
 	for i := range plan.Stages {
 		stage := plan.Stages[i]
 		stagePipeline = append(stagePipeline, func(ctx context.Context) error {
 			pipeline := make([]common.Executor, 0)
+			cancelMatrixes := make([]context.CancelCauseFunc, 0)
+			defer func() {
+				for _, cancel := range cancelMatrixes {
+					cancel(nil)
+				}
+			}()
 			for _, run := range stage.Runs {
 				log.Debugf("Stages Runs: %v", stage.Runs)
 				stageExecutor := make([]common.Executor, 0)
@@ -157,6 +208,11 @@ func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
 				// log.Debugf("Job.RawSecrets: %v", job.RawSecrets)
 				log.Debugf("Job.Result: %v", job.Result)
 
+				if reused, ok := runner.config.ReusedJobs[JobKey(run.Workflow.File, run.JobID)]; ok {
+					pipeline = append(pipeline, runner.reuseJob(run, reused))
+					continue
+				}
+
 				if job.Strategy != nil {
 					log.Debugf("Job.Strategy.FailFast: %v", job.Strategy.FailFast)
 					log.Debugf("Job.Strategy.MaxParallel: %v", job.Strategy.MaxParallel)
@@ -189,9 +245,21 @@ func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
 					maxParallel = len(matrixes)
 				}
 
+				// all jobs of a matrix share a cancellation context, which is cancelled
+				// as soon as one of them fails if fail-fast is enabled
+				workflowCtx := ctx
+				if cancelCtx, ok := workflowCancelCtxs[run.Workflow]; ok {
+					workflowCtx = common.WithJobCancelContext(ctx, cancelCtx)
+				}
+				matrixCancelCtx, cancelMatrix := newMatrixCancelContext(workflowCtx)
+				cancelMatrixes = append(cancelMatrixes, cancelMatrix)
+				failFast := job.Strategy != nil && job.Strategy.FailFast && len(matrixes) > 1
+
 				for i, matrix := range matrixes {
 					rc := runner.newRunContext(ctx, run, matrix)
 					rc.JobName = rc.Name
+					rc.JobIndex = i
+					rc.JobTotal = len(matrixes)
 					if len(matrixes) > 1 {
 						rc.Name = fmt.Sprintf("%s-%d", rc.Name, i+1)
 					}
@@ -200,13 +268,37 @@ func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
 					}
 					stageExecutor = append(stageExecutor, func(ctx context.Context) error {
 						jobName := fmt.Sprintf("%-*s", maxJobNameLen, rc.String())
+						ctx = WithJobLogger(ctx, rc.Run.JobID, jobName, rc.Config, &rc.Masks, matrix)
+						if runner.config.RecordJob != nil {
+							recorder := withJobRecorder(ctx, rc)
+							defer func() { runner.config.RecordJob(recorder.record(rc)) }()
+						}
+						if runner.config.LogDir != "" {
+							if logFiles, err := withJobLogFiles(ctx, rc, runner.config.LogDir); err != nil {
+								common.Logger(ctx).Warnf("Unable to write the log of the job to %s: %v", runner.config.LogDir, err)
+							} else {
+								defer logFiles.close()
+							}
+						}
+						if isJobCancelled(matrixCancelCtx) {
+							rc.result(MergeJobResults(rc.Run.Job().Result, "cancelled"))
+							common.Logger(ctx).WithField("jobResult", "cancelled").Infof("\U0001F3C1  Job cancelled, %v", context.Cause(matrixCancelCtx))
+							rc.emit(Event{Type: EventJobFinished, Result: "cancelled"})
+							return nil
+						}
+
 						executor, err := rc.Executor()
 
 						if err != nil {
 							return err
 						}
 
-						return executor(common.WithJobErrorContainer(WithJobLogger(ctx, rc.Run.JobID, jobName, rc.Config, &rc.Masks, matrix)))
+						ctx = common.WithJobCancelContext(common.WithJobErrorContainer(ctx), matrixCancelCtx)
+						err = executor(ctx)
+						if failFast && common.JobError(ctx) != nil && !rc.isContinueOnError(ctx) {
+							cancelMatrix(errMatrixFailFast)
+						}
+						return err
 					})
 				}
 				pipeline = append(pipeline, common.NewParallelExecutor(maxParallel, stageExecutor...))
@@ -217,16 +309,62 @@ func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
 		})
 	}
 
-	return common.NewPipelineExecutor(stagePipeline...).Then(handleFailure(plan))
+	return common.NewPipelineExecutor(stagePipeline...)
+}
+
+// validateDispatchInputs checks the inputs of a workflow_dispatch event against the inputs declared by the workflows of the plan
+func (runner *runnerImpl) validateDispatchInputs(plan *model.Plan) error {
+	if runner.config.EventName != "workflow_dispatch" {
+		return nil
+	}
+	event := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(runner.eventJSON), &event); err != nil {
+		return err
+	}
+	provided, _ := event["inputs"].(map[string]interface{})
+
+	environments := EnvironmentNames(runner.config.EnvironmentSecrets, runner.config.EnvironmentVars)
+	var errs []error
+	seen := map[*model.Workflow]bool{}
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			if seen[run.Workflow] {
+				continue
+			}
+			seen[run.Workflow] = true
+			config := run.Workflow.WorkflowDispatchConfig()
+			if config == nil {
+				continue
+			}
+			if _, err := config.ResolveInputs(provided, environments); err != nil {
+				errs = append(errs, fmt.Errorf("invalid inputs for workflow '%s': %w", run.Workflow.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var errMatrixFailFast = errors.New("a job of the matrix failed")
+
+// newMatrixCancelContext creates the cancellation context shared by the jobs of a matrix, inheriting a graceful cancellation of the run
+func newMatrixCancelContext(ctx context.Context) (context.Context, context.CancelCauseFunc) {
+	parent := common.JobCancelContext(ctx)
+	if parent == nil {
+		parent = ctx
+	}
+	return context.WithCancelCause(parent)
 }
 
 func handleFailure(plan *model.Plan) common.Executor {
 	return func(_ context.Context) error {
 		for _, stage := range plan.Stages {
 			for _, run := range stage.Runs {
-				if run.Job().Result == "failure" {
+				if run.Job().Failed() {
 					return fmt.Errorf("Job '%s' failed", run.String())
 				}
+				if run.Job().Result == "cancelled" {
+					return fmt.Errorf("Job '%s' was cancelled", run.String())
+				}
 			}
 		}
 		return nil
@@ -252,11 +390,36 @@ func selectMatrixes(originalMatrixes []map[string]interface{}, targetMatrixValue
 	return matrixes
 }
 
+// scheduleEventJSON fills github.event.schedule with the cron expression of the workflow firing at the schedule time,
+// or with its first cron expression if no time is simulated
+func (runner *runnerImpl) scheduleEventJSON(w *model.Workflow) string {
+	crons := w.Schedules()
+	event := map[string]interface{}{}
+	if len(crons) == 0 || json.Unmarshal([]byte(runner.eventJSON), &event) != nil || event["schedule"] != nil {
+		return runner.eventJSON
+	}
+	event["schedule"] = crons[0]
+	if !runner.config.ScheduleTime.IsZero() {
+		if expr, ok, _ := w.ScheduleAt(runner.config.ScheduleTime); ok {
+			event["schedule"] = expr
+		}
+	}
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return runner.eventJSON
+	}
+	return string(eventJSON)
+}
+
 func (runner *runnerImpl) newRunContext(ctx context.Context, run *model.Run, matrix map[string]interface{}) *RunContext {
+	eventJSON := runner.eventJSON
+	if runner.config.EventName == "schedule" {
+		eventJSON = runner.scheduleEventJSON(run.Workflow)
+	}
 	rc := &RunContext{
 		Config:      runner.config,
 		Run:         run,
-		EventJSON:   runner.eventJSON,
+		EventJSON:   eventJSON,
 		StepResults: make(map[string]*model.StepResult),
 		Matrix:      matrix,
 		caller:      runner.caller,
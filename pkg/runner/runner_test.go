@@ -13,11 +13,13 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
 	assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
 	"github.com/nektos/act/pkg/common"
@@ -189,11 +191,21 @@ func (j *TestJobFileInfo) runTest(ctx context.Context, t *testing.T, cfg *Config
 		ReuseContainers:       false,
 		Env:                   cfg.Env,
 		Secrets:               cfg.Secrets,
+		EnvironmentSecrets:    cfg.EnvironmentSecrets,
+		EnvironmentVars:       cfg.EnvironmentVars,
+		ApproveEnvironment:    cfg.ApproveEnvironment,
 		Inputs:                cfg.Inputs,
 		GitHubInstance:        "github.com",
 		ContainerArchitecture: cfg.ContainerArchitecture,
 		Matrix:                cfg.Matrix,
 		ActionCache:           cfg.ActionCache,
+		BreakOnFailure:        cfg.BreakOnFailure,
+		BreakBefore:           cfg.BreakBefore,
+		Debugger:              cfg.Debugger,
+		FromStep:              cfg.FromStep,
+		ToStep:                cfg.ToStep,
+		OnlySteps:             cfg.OnlySteps,
+		RecordJob:             cfg.RecordJob,
 	}
 
 	runner, err := New(runnerConfig)
@@ -442,6 +454,101 @@ func TestPullAndPostStepFailureIsJobFailure(t *testing.T) {
 	}
 }
 
+func TestRunMatrixFailFast(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	factory := &captureJobLoggerFactory{}
+	logger := logrus.New()
+	logger.SetOutput(&factory.buffer)
+	logger.SetLevel(log.TraceLevel)
+	logger.SetFormatter(&log.JSONFormatter{})
+
+	table := TestJobFileInfo{workdir, "matrix-fail-fast", "push", "Job 'fail-fast' failed", map[string]string{"ubuntu-latest": "-self-hosted"}, secrets}
+	table.runTest(common.WithLogger(WithJobLoggerFactory(t.Context(), factory), logger), t, &Config{})
+
+	scan := bufio.NewScanner(&factory.buffer)
+	jobResults := []interface{}{}
+	stepResults := []interface{}{}
+	for scan.Scan() {
+		t.Log(scan.Text())
+		entry := map[string]interface{}{}
+		if json.Unmarshal(scan.Bytes(), &entry) == nil {
+			if val, ok := entry["jobResult"]; ok {
+				jobResults = append(jobResults, val)
+			}
+			if val, ok := entry["stepResult"]; ok && entry["stage"] == "Main" {
+				stepResults = append(stepResults, val)
+			}
+		}
+	}
+	// the first job fails after checking the strategy context, the other jobs of the matrix are cancelled before they start
+	assert.Equal(t, []interface{}{"failure", "cancelled", "cancelled"}, jobResults)
+	assert.Equal(t, []interface{}{"success", "failure"}, stepResults)
+}
+
+func TestRunGracefulCancellation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	factory := &captureJobLoggerFactory{}
+	logger := logrus.New()
+	logger.SetOutput(&factory.buffer)
+	logger.SetFormatter(&log.JSONFormatter{})
+
+	// the run is cancelled like by Ctrl-C, once the long running step started
+	started := filepath.Join(t.TempDir(), "started")
+	jobCancelCtx, cancel := context.WithCancelCause(t.Context())
+	defer cancel(nil)
+	go func() {
+		for {
+			if _, err := os.Stat(started); err == nil {
+				cancel(common.ErrRunCancelled)
+				return
+			}
+			select {
+			case <-jobCancelCtx.Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+
+	ctx := common.WithJobCancelContext(common.WithLogger(WithJobLoggerFactory(t.Context(), factory), logger), jobCancelCtx)
+	table := TestJobFileInfo{workdir, "graceful-cancel", "push", "Job 'cancel' was cancelled", map[string]string{"ubuntu-latest": "-self-hosted"}, secrets}
+	table.runTest(ctx, t, &Config{Env: map[string]string{"STARTED": started}})
+
+	scan := bufio.NewScanner(&factory.buffer)
+	jobResults := []interface{}{}
+	stepResults := map[string]interface{}{}
+	output := []string{}
+	for scan.Scan() {
+		entry := map[string]interface{}{}
+		if json.Unmarshal(scan.Bytes(), &entry) == nil {
+			if val, ok := entry["jobResult"]; ok {
+				jobResults = append(jobResults, val)
+			}
+			if val, ok := entry["stepResult"]; ok && entry["stage"] == "Main" {
+				stepResults[entry["step"].(string)] = val
+			}
+			if entry["raw_output"] == true {
+				output = append(output, strings.TrimSpace(entry["msg"].(string)))
+			}
+		}
+	}
+	// the running step is interrupted, the steps using cancelled() or always() still run
+	assert.Equal(t, []interface{}{"cancelled"}, jobResults)
+	assert.Equal(t, map[string]interface{}{
+		"long running": "failure",
+		"skipped":      "skipped",
+		"cancelled":    "success",
+		"always":       "success",
+	}, stepResults)
+	assert.Equal(t, []string{"interrupted", "cancelled", "always"}, output)
+}
+
 type mockCache struct {
 }
 
@@ -566,6 +673,8 @@ func TestRunEventHostEnvironment(t *testing.T) {
 			{workdir, "steps-context/conclusion", "push", "", platforms, secrets},
 			{workdir, "steps-context/outcome", "push", "", platforms, secrets},
 			{workdir, "job-status-check", "push", "job 'fail' failed", platforms, secrets},
+			{workdir, "job-continue-on-error", "push", "", platforms, secrets},
+			{workdir, "concurrency", "push", "", platforms, secrets},
 			{workdir, "if-expressions", "push", "Job 'mytest' failed", platforms, secrets},
 			{workdir, "uses-action-with-pre-and-post-step", "push", "", platforms, secrets},
 			{workdir, "evalenv", "push", "", platforms, secrets},
@@ -756,11 +865,51 @@ func TestRunActionInputs(t *testing.T) {
 
 	inputs := map[string]string{
 		"SOME_INPUT": "input",
+		"NAME":       "name",
+		"SOME_VALUE": "value",
 	}
 
 	tjfi.runTest(context.Background(), t, &Config{Inputs: inputs})
 }
 
+func TestRunWorkflowDispatchInputs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	workflowPath := "workflow_dispatch-typed"
+
+	tjfi := TestJobFileInfo{
+		workdir:      workdir,
+		workflowPath: workflowPath,
+		eventName:    "workflow_dispatch",
+		errorMessage: "",
+		platforms:    map[string]string{"ubuntu-latest": "-self-hosted"},
+	}
+	tjfi.runTest(context.Background(), t, &Config{EventPath: filepath.Join(workdir, workflowPath, "event.json")})
+
+	t.Run("invalid", func(t *testing.T) {
+		runner, err := New(&Config{
+			Workdir:   workdir,
+			EventName: "workflow_dispatch",
+			EventPath: filepath.Join(workdir, workflowPath, "invalid.json"),
+			Platforms: tjfi.platforms,
+			EnvironmentVars: map[string]map[string]string{
+				"staging": {"TARGET": "staging"},
+			},
+		})
+		require.NoError(t, err)
+		planner, err := model.NewWorkflowPlanner(filepath.Join(workdir, workflowPath), true, false)
+		require.NoError(t, err)
+		plan, err := planner.PlanEvent("workflow_dispatch")
+		require.NoError(t, err)
+
+		err = runner.NewPlanExecutor(plan)(context.Background())
+		assert.ErrorContains(t, err, "input 'count': 'three' is not a number")
+		assert.ErrorContains(t, err, "input 'level': 'trace' is not one of the options [info debug]")
+		assert.ErrorContains(t, err, "input 'target': 'qa' is not one of the environments [staging]")
+	})
+}
+
 func TestRunEventPullRequest(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -805,3 +954,37 @@ func TestRunMatrixWithUserDefinedInclusions(t *testing.T) {
 
 	tjfi.runTest(context.Background(), t, &Config{Matrix: matrix})
 }
+
+func TestScheduleEventJSON(t *testing.T) {
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+on:
+  schedule:
+    - cron: "0 3 * * *"
+    - cron: "30 12 * * 1-5"
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: exit 0
+`), false)
+	require.NoError(t, err)
+
+	at, err := time.Parse(time.RFC3339, "2024-01-01T12:30:00Z")
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name      string
+		eventJSON string
+		at        time.Time
+		expected  string
+	}{
+		{"first-cron", "{}", time.Time{}, `{"schedule":"0 3 * * *"}`},
+		{"matching-cron", "{}", at, `{"schedule":"30 12 * * 1-5"}`},
+		{"event-file", `{"schedule":"* * * * *"}`, at, `{"schedule":"* * * * *"}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &runnerImpl{config: &Config{EventName: "schedule", ScheduleTime: tt.at}, eventJSON: tt.eventJSON}
+			assert.JSONEq(t, tt.expected, runner.scheduleEventJSON(workflow))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package runner
 
 import (
 	"archive/tar"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/container"
@@ -53,6 +55,9 @@ func (s stepStage) String() string {
 	return "Unknown"
 }
 
+// maxStepSummarySize is the limit of GitHub for the summary of a step
+const maxStepSummarySize = 1024 * 1024
+
 func processRunnerSummaryCommand(ctx context.Context, fileName string, rc *RunContext) error {
 	if common.Dryrun(ctx) {
 		return nil
@@ -75,10 +80,27 @@ func processRunnerSummaryCommand(ctx context.Context, fileName string, rc *RunCo
 	if len(summary) == 0 {
 		return nil
 	}
+	if len(summary) > maxStepSummarySize {
+		common.Logger(ctx).Warnf("  \U0001F6A7  Summary of %d bytes exceeds the limit of %d bytes of GitHub, it is truncated", len(summary), maxStepSummarySize)
+		summary = truncateStepSummary(summary)
+	}
 	common.Logger(ctx).WithFields(logrus.Fields{"command": "summary", "content": string(summary)}).Infof("  \U00002699  Summary - %s", string(summary))
 	return nil
 }
 
+// truncateStepSummary cuts a summary to the limit of GitHub after its last complete line and appends a marker
+func truncateStepSummary(summary []byte) []byte {
+	marker := fmt.Sprintf("\n\n*The summary was truncated, its %d bytes exceed the limit of %d bytes.*\n", len(summary), maxStepSummarySize)
+	cut := maxStepSummarySize - len(marker)
+	if i := bytes.LastIndexByte(summary[:cut], '\n'); i > 0 {
+		cut = i
+	}
+	for cut > 0 && !utf8.RuneStart(summary[cut]) {
+		cut--
+	}
+	return append(summary[:cut:cut], marker...)
+}
+
 func processRunnerEnvFileCommand(ctx context.Context, fileName string, rc *RunContext, setter func(context.Context, map[string]string, string)) error {
 	env := map[string]string{}
 	err := rc.JobContainer.UpdateFromEnv(path.Join(rc.JobContainer.GetActPath(), fileName), &env)(ctx)
@@ -13,6 +13,13 @@ type stepFactory interface {
 type stepFactoryImpl struct{}
 
 func (sf *stepFactoryImpl) newStep(stepModel *model.Step, rc *RunContext) (step, error) {
+	if override := rc.stepOverride(stepModel); override != nil {
+		return sf.newOverriddenStep(override, stepModel, rc)
+	}
+	return sf.newStepOfType(stepModel, rc)
+}
+
+func (sf *stepFactoryImpl) newStepOfType(stepModel *model.Step, rc *RunContext) (step, error) {
 	switch stepModel.Type() {
 	case model.StepTypeInvalid:
 		return nil, fmt.Errorf("Invalid run/uses syntax for job:%s step:%+v", rc.Run, stepModel)
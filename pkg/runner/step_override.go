@@ -0,0 +1,233 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/workflowpattern"
+)
+
+// StepOverride replaces the steps matched by their uses and/or their id when running locally, e.g. deploys or notifications.
+// A matched step is skipped, runs a shell script or another action instead, or gets the outputs and the outcome
+// of the override without running at all.
+type StepOverride struct {
+	Job  string `yaml:"job"`  // id of the job, the steps of any job if empty
+	ID   string `yaml:"id"`   // id of the step
+	Uses string `yaml:"uses"` // pattern of the uses of the step, with the syntax of the patterns of the branch filters
+
+	Skip    bool              `yaml:"skip"`
+	Run     string            `yaml:"run"`
+	Shell   string            `yaml:"shell"`
+	Action  string            `yaml:"action"` // local or remote action, run with the inputs of the step
+	With    map[string]string `yaml:"with"`   // inputs of the action, in addition to the inputs of the step
+	Outputs map[string]string `yaml:"outputs"`
+	Outcome string            `yaml:"outcome"` // success or failure
+
+	pattern *workflowpattern.WorkflowPattern
+}
+
+type stepOverridesFile struct {
+	Steps []*StepOverride `yaml:"steps"`
+}
+
+// ReadStepOverrides reads the overrides of steps from a YAML file with a list of overrides under steps
+func ReadStepOverrides(file string) ([]*StepOverride, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	overrides := stepOverridesFile{}
+	if err := yaml.Unmarshal(content, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to read the step overrides '%s': %w", file, err)
+	}
+	for i, override := range overrides.Steps {
+		if err := override.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid step override %d in '%s': %w", i+1, file, err)
+		}
+	}
+	return overrides.Steps, nil
+}
+
+// Validate checks an override and compiles the pattern of its uses, an override has to be valid to match steps
+func (o *StepOverride) Validate() error {
+	if o.ID == "" && o.Uses == "" {
+		return fmt.Errorf("an override needs the id or the uses of the steps it replaces")
+	}
+	if o.Uses != "" {
+		pattern, err := workflowpattern.CompilePattern(o.Uses)
+		if err != nil {
+			return fmt.Errorf("invalid uses pattern '%s': %w", o.Uses, err)
+		}
+		o.pattern = pattern
+	}
+	replacements := 0
+	for _, set := range []bool{o.Skip, o.Run != "", o.Action != "", len(o.Outputs) > 0 || o.Outcome != ""} {
+		if set {
+			replacements++
+		}
+	}
+	if replacements != 1 {
+		return fmt.Errorf("an override needs exactly one of skip, run, action or outputs and outcome")
+	}
+	if o.Shell != "" && o.Run == "" {
+		return fmt.Errorf("the shell of an override needs run")
+	}
+	if len(o.With) > 0 && o.Action == "" {
+		return fmt.Errorf("the inputs of an override under with need action")
+	}
+	if o.Outcome != "" && o.Outcome != "success" && o.Outcome != "failure" {
+		return fmt.Errorf("invalid outcome '%s', expected success or failure", o.Outcome)
+	}
+	return nil
+}
+
+func (o *StepOverride) matches(jobID string, stepModel *model.Step) bool {
+	if o.Job != "" && o.Job != jobID {
+		return false
+	}
+	if o.ID != "" && o.ID != stepModel.ID {
+		return false
+	}
+	if o.Uses != "" {
+		if o.pattern == nil || stepModel.Uses == "" || !o.pattern.Regex.MatchString(stepModel.Uses) {
+			return false
+		}
+	}
+	return true
+}
+
+// String describes the replacement of the override for the log
+func (o *StepOverride) String() string {
+	switch {
+	case o.Skip:
+		return "skip"
+	case o.Run != "":
+		return "run"
+	case o.Action != "":
+		return fmt.Sprintf("action %s", o.Action)
+	}
+	return "outputs"
+}
+
+// stepOverride returns the first override matching a step of the job of the run context, if any
+func (rc *RunContext) stepOverride(stepModel *model.Step) *StepOverride {
+	if rc.Config == nil {
+		return nil
+	}
+	jobID := ""
+	if rc.Run != nil {
+		jobID = rc.Run.JobID
+	}
+	for _, override := range rc.Config.StepOverrides {
+		if override.matches(jobID, stepModel) {
+			return override
+		}
+	}
+	return nil
+}
+
+// newOverriddenStep creates the step replacing a step matched by an override, the step keeps its id,
+// its name is marked as overridden in the log
+func (sf *stepFactoryImpl) newOverriddenStep(override *StepOverride, stepModel *model.Step, rc *RunContext) (step, error) {
+	replacement := *stepModel
+	replacement.Name = fmt.Sprintf("%s (overridden: %s)", stepModel.String(), override)
+	switch {
+	case override.Run != "":
+		replacement.Uses = ""
+		replacement.With = nil
+		replacement.Run = override.Run
+		if override.Shell != "" {
+			replacement.Shell = override.Shell
+		}
+	case override.Action != "":
+		replacement.Run = ""
+		replacement.Uses = override.Action
+		replacement.With = map[string]string{}
+		for name, value := range stepModel.With {
+			replacement.With[name] = value
+		}
+		for name, value := range override.With {
+			replacement.With[name] = value
+		}
+	default:
+		return &stepOverridden{Step: &replacement, RunContext: rc, override: override}, nil
+	}
+	return sf.newStepOfType(&replacement, rc)
+}
+
+// stepOverridden is a step which is skipped, or sets the outputs and the outcome of an override without running
+type stepOverridden struct {
+	Step       *model.Step
+	RunContext *RunContext
+	override   *StepOverride
+	env        map[string]string
+}
+
+func (so *stepOverridden) pre() common.Executor {
+	return func(_ context.Context) error {
+		return nil
+	}
+}
+
+func (so *stepOverridden) main() common.Executor {
+	if so.override.Skip {
+		return func(ctx context.Context) error {
+			rc := so.getRunContext()
+			rc.StepResults[so.Step.ID] = &model.StepResult{
+				Outcome:    model.StepStatusSkipped,
+				Conclusion: model.StepStatusSkipped,
+				Outputs:    make(map[string]string),
+			}
+			common.Logger(ctx).WithField("stepResult", model.StepStatusSkipped).Infof("\U0001F3AD  Skip %s", rc.ExprEval.Interpolate(ctx, so.Step.String()))
+			return nil
+		}
+	}
+	so.env = map[string]string{}
+	return runStepExecutor(so, stepStageMain, func(ctx context.Context) error {
+		rc := so.getRunContext()
+		names := make([]string, 0, len(so.override.Outputs))
+		for name := range so.override.Outputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			rc.setOutput(ctx, map[string]string{"name": name}, rc.ExprEval.Interpolate(ctx, so.override.Outputs[name]))
+		}
+		if so.override.Outcome == "failure" {
+			return fmt.Errorf("the outcome of the step is overridden to be a failure")
+		}
+		return nil
+	})
+}
+
+func (so *stepOverridden) post() common.Executor {
+	return func(_ context.Context) error {
+		return nil
+	}
+}
+
+func (so *stepOverridden) getRunContext() *RunContext {
+	return so.RunContext
+}
+
+func (so *stepOverridden) getGithubContext(ctx context.Context) *model.GithubContext {
+	return so.getRunContext().getGithubContext(ctx)
+}
+
+func (so *stepOverridden) getStepModel() *model.Step {
+	return so.Step
+}
+
+func (so *stepOverridden) getEnv() *map[string]string {
+	return &so.env
+}
+
+func (so *stepOverridden) getIfExpression(_ context.Context, _ stepStage) string {
+	return so.Step.If.Value
+}
@@ -0,0 +1,132 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestReadStepOverrides(t *testing.T) {
+	table := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{name: "valid", content: "steps:\n  - uses: actions/*@*\n    skip: true\n  - id: deploy\n    outcome: failure\n"},
+		{name: "no match", content: "steps:\n  - skip: true\n", err: "needs the id or the uses"},
+		{name: "no replacement", content: "steps:\n  - id: deploy\n", err: "exactly one of"},
+		{name: "two replacements", content: "steps:\n  - id: deploy\n    run: echo\n    action: ./deploy\n", err: "exactly one of"},
+		{name: "shell without run", content: "steps:\n  - id: deploy\n    skip: true\n    shell: bash\n", err: "shell of an override needs run"},
+		{name: "with without action", content: "steps:\n  - id: deploy\n    run: echo\n    with:\n      env: dev\n", err: "under with need action"},
+		{name: "invalid outcome", content: "steps:\n  - id: deploy\n    outcome: skipped\n", err: "invalid outcome 'skipped'"},
+		{name: "invalid pattern", content: "steps:\n  - uses: '[a-'\n    skip: true\n", err: "invalid uses pattern"},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "overrides.yml")
+			require.NoError(t, os.WriteFile(file, []byte(tt.content), 0o600))
+			overrides, err := ReadStepOverrides(file)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, overrides, 2)
+		})
+	}
+}
+
+func TestStepFactoryOverrides(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "overrides.yml")
+	require.NoError(t, os.WriteFile(file, []byte(`steps:
+  - uses: slackapi/*@*
+    skip: true
+  - job: deploy
+    id: deploy
+    action: ./mocks/deploy
+    with:
+      dry-run: "true"
+  - id: publish
+    run: echo publish
+    shell: sh
+`), 0o600))
+	overrides, err := ReadStepOverrides(file)
+	require.NoError(t, err)
+
+	table := []struct {
+		name  string
+		job   string
+		model *model.Step
+		check func(t *testing.T, s step)
+	}{
+		{
+			name:  "skip",
+			job:   "build",
+			model: &model.Step{ID: "0", Uses: "slackapi/slack-github-action@v1"},
+			check: func(t *testing.T, s step) {
+				assert.IsType(t, &stepOverridden{}, s)
+				assert.Equal(t, "slackapi/slack-github-action@v1 (overridden: skip)", s.getStepModel().String())
+			},
+		},
+		{
+			name:  "action",
+			job:   "deploy",
+			model: &model.Step{ID: "deploy", Name: "Deploy", Uses: "example/deploy@v1", With: map[string]string{"environment": "production"}},
+			check: func(t *testing.T, s step) {
+				assert.IsType(t, &stepActionLocal{}, s)
+				assert.Equal(t, &model.Step{
+					ID:   "deploy",
+					Name: "Deploy (overridden: action ./mocks/deploy)",
+					Uses: "./mocks/deploy",
+					With: map[string]string{"environment": "production", "dry-run": "true"},
+				}, s.getStepModel())
+			},
+		},
+		{
+			name:  "other job",
+			job:   "build",
+			model: &model.Step{ID: "deploy", Uses: "example/deploy@v1"},
+			check: func(t *testing.T, s step) {
+				assert.IsType(t, &stepActionRemote{}, s)
+			},
+		},
+		{
+			name:  "run",
+			job:   "deploy",
+			model: &model.Step{ID: "publish", Uses: "example/publish@v1", With: map[string]string{"token": "x"}},
+			check: func(t *testing.T, s step) {
+				assert.IsType(t, &stepRun{}, s)
+				assert.Equal(t, &model.Step{
+					ID:    "publish",
+					Name:  "example/publish@v1 (overridden: run)",
+					Run:   "echo publish",
+					Shell: "sh",
+				}, s.getStepModel())
+			},
+		},
+		{
+			name:  "pattern does not match",
+			job:   "build",
+			model: &model.Step{ID: "0", Uses: "slackapi/slack/notify@v1"},
+			check: func(t *testing.T, s step) {
+				assert.IsType(t, &stepActionRemote{}, s)
+			},
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := &RunContext{
+				Config: &Config{StepOverrides: overrides},
+				Run:    &model.Run{JobID: tt.job},
+			}
+			s, err := (&stepFactoryImpl{}).newStep(tt.model, rc)
+			require.NoError(t, err)
+			tt.check(t, s)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+// selectSteps returns whether each step of the job runs with the FromStep, ToStep and OnlySteps of the config,
+// nil if all steps run. A selector of another job does not restrict the steps of the job, while all steps of the job
+// are skipped if a selector without a job does not match any of its steps.
+func selectSteps(rc *RunContext, steps []*model.Step) ([]bool, error) {
+	if rc.Config.FromStep == "" && rc.Config.ToStep == "" && len(rc.Config.OnlySteps) == 0 {
+		return nil, nil
+	}
+
+	selected := make([]bool, len(steps))
+	from, fromApplies, err := findStep(rc, steps, rc.Config.FromStep)
+	if err != nil {
+		return nil, err
+	}
+	to, toApplies, err := findStep(rc, steps, rc.Config.ToStep)
+	if err != nil {
+		return nil, err
+	}
+	if (fromApplies && from < 0) || (toApplies && to < 0) {
+		return selected, nil
+	}
+	if fromApplies && toApplies && from > to {
+		return nil, fmt.Errorf("step '%s' of --from-step comes after step '%s' of --to-step in job '%s'", rc.Config.FromStep, rc.Config.ToStep, rc.Run.JobID)
+	}
+	if !fromApplies {
+		from = 0
+	}
+	if !toApplies {
+		to = len(steps) - 1
+	}
+	for i := from; i <= to; i++ {
+		selected[i] = true
+	}
+
+	only := []int{}
+	restricted := false
+	for _, selector := range rc.Config.OnlySteps {
+		i, applies, err := findStep(rc, steps, selector)
+		if err != nil {
+			return nil, err
+		}
+		restricted = restricted || applies
+		if i >= 0 {
+			only = append(only, i)
+		}
+	}
+	if restricted {
+		for i := range selected {
+			selected[i] = false
+		}
+		for _, i := range only {
+			selected[i] = true
+		}
+	}
+	return selected, nil
+}
+
+// findStep returns the index of the step matching a selector of the form [<job>.]<step-id or index>, -1 if no step
+// matches, and whether the selector applies to the job. A selector of the job which does not match fails.
+func findStep(rc *RunContext, steps []*model.Step, selector string) (int, bool, error) {
+	if selector == "" {
+		return -1, false, nil
+	}
+	i, applies := matchStep(rc.Run.JobID, steps, selector)
+	if _, step, qualified := strings.Cut(selector, "."); applies && i < 0 && qualified {
+		return -1, true, fmt.Errorf("step '%s' not found in job '%s'", step, rc.Run.JobID)
+	}
+	return i, applies, nil
+}
+
+// matchStep returns the index of the step of a job matching a selector, -1 if no step matches,
+// and whether the selector applies to the job
+func matchStep(jobID string, steps []*model.Step, selector string) (int, bool) {
+	if selector == "" {
+		return -1, false
+	}
+	if job, step, qualified := strings.Cut(selector, "."); qualified {
+		if job != jobID {
+			return -1, false
+		}
+		selector = step
+	}
+	return stepIndex(steps, selector), true
+}
+
+// stepIndex returns the index of the step with the id, -1 if there is none
+func stepIndex(steps []*model.Step, id string) int {
+	for i, step := range steps {
+		if step != nil && step.ID == id {
+			return i
+		}
+	}
+	// steps without an id are selected by their index, which is also their default id
+	if i, err := strconv.Atoi(id); err == nil && i >= 0 && i < len(steps) {
+		return i
+	}
+	return -1
+}
+
+// validateStepSelectors checks that every selector without a job matches a step of a job of the plan, and that
+// every selector of a job names a job of the plan which has the step. The jobs of reusable workflows are only known
+// once they run, a selector of a job which is not in the plan is then checked by the job.
+// The steps of --only-step can't be combined with the range of --from-step and --to-step, and the range of a job
+// can't be empty.
+func validateStepSelectors(config *Config, plan *model.Plan) error {
+	if len(config.OnlySteps) > 0 && (config.FromStep != "" || config.ToStep != "") {
+		return fmt.Errorf("--only-step can't be combined with --from-step or --to-step")
+	}
+
+	reusable := false
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			job := run.Job()
+			if job == nil {
+				continue
+			}
+			if job.Uses != "" {
+				reusable = true
+			}
+			from, fromApplies := matchStep(run.JobID, job.Steps, config.FromStep)
+			to, toApplies := matchStep(run.JobID, job.Steps, config.ToStep)
+			if fromApplies && toApplies && from >= 0 && to >= 0 && from > to {
+				return fmt.Errorf("step '%s' of --from-step comes after step '%s' of --to-step in job '%s'", config.FromStep, config.ToStep, run.JobID)
+			}
+		}
+	}
+
+	selectors := append([]string{config.FromStep, config.ToStep}, config.OnlySteps...)
+	for _, selector := range selectors {
+		if selector == "" {
+			continue
+		}
+		jobID, step, qualified := strings.Cut(selector, ".")
+		if !qualified {
+			step, jobID = selector, ""
+		}
+		planned, found := false, false
+		for _, stage := range plan.Stages {
+			for _, run := range stage.Runs {
+				if qualified && run.JobID != jobID {
+					continue
+				}
+				planned = true
+				if job := run.Job(); job != nil && stepIndex(job.Steps, step) >= 0 {
+					found = true
+				}
+			}
+		}
+		switch {
+		case found:
+		case !qualified:
+			return fmt.Errorf("step '%s' not found in any job", selector)
+		case planned:
+			return fmt.Errorf("step '%s' not found in job '%s'", step, jobID)
+		case !reusable:
+			return fmt.Errorf("job '%s' of the step selector '%s' not found", jobID, selector)
+		}
+	}
+	return nil
+}
+
+// skipStep reports a step which is not selected as skipped in the steps context
+func skipStep(rc *RunContext, stepModel *model.Step) common.Executor {
+	return func(ctx context.Context) error {
+		rc.StepResults[stepModel.ID] = &model.StepResult{
+			Outcome:    model.StepStatusSkipped,
+			Conclusion: model.StepStatusSkipped,
+			Outputs:    make(map[string]string),
+		}
+		common.Logger(ctx).WithField("stepResult", model.StepStatusSkipped).Infof("Skipping step '%s', it is not selected by --from-step, --to-step or --only-step", rc.ExprEval.Interpolate(ctx, stepModel.String()))
+		return nil
+	}
+}
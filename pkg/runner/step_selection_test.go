@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStepSelection(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	for name, tt := range map[string]struct {
+		config       Config
+		errorMessage string
+		conclusions  map[string][]string
+	}{
+		"from-step": {Config{FromStep: "test.test"}, "", map[string][]string{
+			"test": {"skipped", "skipped", "success", "success"},
+			"lint": {"success"},
+		}},
+		"to-step-by-index": {Config{ToStep: "test.1"}, "", map[string][]string{
+			"test": {"success", "success", "skipped", "skipped"},
+			"lint": {"success"},
+		}},
+		"only-step": {Config{OnlySteps: []string{"checkout", "report"}}, "", map[string][]string{
+			"test": {"success", "skipped", "skipped", "success"},
+			"lint": {"skipped"},
+		}},
+		"from-step-of-some-jobs": {Config{FromStep: "test"}, "", map[string][]string{
+			"test": {"skipped", "skipped", "success", "success"},
+			"lint": {"skipped"},
+		}},
+		"only-step-of-job": {Config{OnlySteps: []string{"test.report"}}, "", map[string][]string{
+			"test": {"skipped", "skipped", "skipped", "success"},
+			"lint": {"success"},
+		}},
+		"unknown-step":             {Config{FromStep: "test.deploy"}, "step 'deploy' not found in job 'test'", map[string][]string{}},
+		"from-step-after-to-step":  {Config{FromStep: "report", ToStep: "test.checkout"}, "step 'report' of --from-step comes after step 'test.checkout' of --to-step in job 'test'", map[string][]string{}},
+		"only-step-with-range":     {Config{FromStep: "test.test", OnlySteps: []string{"report"}}, "--only-step can't be combined with --from-step or --to-step", map[string][]string{}},
+		"unknown-job":              {Config{OnlySteps: []string{"tset.test"}}, "job 'tset' of the step selector 'tset.test' not found", map[string][]string{}},
+		"unknown-step-in-all-jobs": {Config{OnlySteps: []string{"lint", "deploy"}}, "step 'deploy' not found in any job", map[string][]string{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			conclusions := map[string][]string{}
+			outputs := map[string]string{}
+			config := tt.config
+			config.RecordJob = func(record JobRecord) {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, step := range record.Steps {
+					conclusions[record.JobID] = append(conclusions[record.JobID], step.Conclusion)
+					if step.ID == "report" {
+						outputs = step.Outputs
+					}
+				}
+			}
+			table := TestJobFileInfo{workdir, "step-selection", "push", tt.errorMessage, map[string]string{"ubuntu-latest": "-self-hosted"}, secrets}
+			table.runTest(t.Context(), t, &config)
+			assert.Equal(t, tt.conclusions, conclusions)
+			if conclusions["test"] != nil && conclusions["test"][3] == "success" {
+				// skipped steps are reported as skipped in the steps context
+				assert.Equal(t, map[string]string{"test": conclusions["test"][2]}, outputs)
+			}
+		})
+	}
+}
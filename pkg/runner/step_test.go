@@ -2,7 +2,10 @@ package runner
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/model"
@@ -345,3 +348,18 @@ func TestIsContinueOnError(t *testing.T) {
 	assertObject.False(continueOnError)
 	assertObject.NotNil(err)
 }
+
+func TestTruncateStepSummary(t *testing.T) {
+	line := strings.Repeat("é", 99) + "\n"
+	summary := []byte(strings.Repeat(line, maxStepSummarySize/len(line)+10))
+
+	truncated := truncateStepSummary(summary)
+	assert.LessOrEqual(t, len(truncated), maxStepSummarySize)
+	assert.True(t, utf8.Valid(truncated))
+	content, marker, found := strings.Cut(string(truncated), "\n\n*The summary was truncated")
+	assert.True(t, found)
+	assert.Equal(t, fmt.Sprintf(", its %d bytes exceed the limit of %d bytes.*\n", len(summary), maxStepSummarySize), marker)
+	// the summary is cut after its last complete line
+	assert.True(t, strings.HasPrefix(string(summary), content+"\n"))
+	assert.Zero(t, len(content+"\n")%len(line))
+}
@@ -0,0 +1,162 @@
+// Package workflowtest runs test cases of workflows, a test case triggers an event and checks the results
+// of the jobs and steps, their outputs, the uploaded artifacts and the logs of the run.
+package workflowtest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/runner"
+)
+
+// Case is a test case of workflows, a file can hold several cases as YAML documents separated by ---
+type Case struct {
+	Name     string                 `yaml:"name"`
+	Workflow string                 `yaml:"workflow"` // file of the workflow relative to the workflows path, all workflows if empty
+	Event    string                 `yaml:"event"`
+	Job      string                 `yaml:"job"`     // id of the job to run, all jobs triggered by the event if empty
+	Payload  map[string]interface{} `yaml:"payload"` // payload of the event
+	Inputs   map[string]string      `yaml:"inputs"`
+	Secrets  map[string]string      `yaml:"secrets"`
+	Vars     map[string]string      `yaml:"vars"`
+	Env      map[string]string      `yaml:"env"`
+	Matrix   map[string]interface{} `yaml:"matrix"` // the matrix combinations to run, a value or a list of values by key
+	Mocks    []*runner.StepOverride `yaml:"mocks"`  // overrides of steps like the ones of --step-overrides
+	Expect   Expect                 `yaml:"expect"`
+
+	File string `yaml:"-"` // file the case was read from
+}
+
+// Expect are the expected results of a test case, only the given results are checked
+type Expect struct {
+	Result    string                `yaml:"result"` // result of the run: success, failure or cancelled, success if empty
+	Jobs      map[string]*JobExpect `yaml:"jobs"`   // by job id, or by the name of a job including its matrix
+	Artifacts []string              `yaml:"artifacts"`
+	Logs      []string              `yaml:"logs"` // substrings of the logs of the jobs
+}
+
+// JobExpect are the expected results of a job, every matrix combination of the job has to match them
+type JobExpect struct {
+	Result  string                 `yaml:"result"`
+	Outputs map[string]string      `yaml:"outputs"`
+	Steps   map[string]*StepExpect `yaml:"steps"` // by step id or name
+	Logs    []string               `yaml:"logs"`
+}
+
+// StepExpect are the expected results of a step
+type StepExpect struct {
+	Outcome    string            `yaml:"outcome"`
+	Conclusion string            `yaml:"conclusion"`
+	Outputs    map[string]string `yaml:"outputs"`
+	Logs       []string          `yaml:"logs"`
+}
+
+// ReadCases reads the test cases of files and of the *.yml and *.yaml files in directories
+func ReadCases(paths ...string) ([]*Case, error) {
+	files := []string{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && (filepath.Ext(file) == ".yml" || filepath.Ext(file) == ".yaml") {
+				files = append(files, file)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+
+	cases := []*Case{}
+	for _, file := range files {
+		fileCases, err := readCaseFile(file)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, fileCases...)
+	}
+	return cases, nil
+}
+
+func readCaseFile(file string) ([]*Case, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cases := []*Case{}
+	decoder := yaml.NewDecoder(f)
+	for {
+		testCase := &Case{}
+		if err := decoder.Decode(testCase); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read the test case '%s': %w", file, err)
+		}
+		testCase.File = file
+		if testCase.Name == "" {
+			testCase.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			if len(cases) > 0 {
+				testCase.Name = fmt.Sprintf("%s #%d", testCase.Name, len(cases)+1)
+			}
+		}
+		if err := testCase.validate(); err != nil {
+			return nil, fmt.Errorf("invalid test case '%s' in '%s': %w", testCase.Name, file, err)
+		}
+		cases = append(cases, testCase)
+	}
+	return cases, nil
+}
+
+func (c *Case) validate() error {
+	if c.Event == "" {
+		return fmt.Errorf("the event of the test case is missing")
+	}
+	for i, mock := range c.Mocks {
+		if err := mock.Validate(); err != nil {
+			return fmt.Errorf("invalid mock %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// MatrixArgs returns the matrix of the case in the form of the --matrix flag, key:value
+func (c *Case) MatrixArgs() []string {
+	args := []string{}
+	for _, key := range sortedKeys(c.Matrix) {
+		values, ok := c.Matrix[key].([]interface{})
+		if !ok {
+			values = []interface{}{c.Matrix[key]}
+		}
+		for _, value := range values {
+			args = append(args, fmt.Sprintf("%s:%v", key, value))
+		}
+	}
+	return args
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,77 @@
+package workflowtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCases(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "deploy"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "build.yml"), []byte(`
+event: push
+matrix:
+  os: [linux, windows]
+  node: 20
+mocks:
+  - uses: example/deploy-action@*
+    outputs:
+      url: https://example.com
+expect:
+  jobs:
+    build:
+      result: success
+      steps:
+        deploy:
+          outputs:
+            url: https://example.com
+---
+name: dispatch
+event: workflow_dispatch
+inputs:
+  version: "1.0"
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deploy", "deploy.yaml"), []byte("event: release\nexpect:\n  result: failure\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# tests\n"), 0o600))
+
+	cases, err := ReadCases(dir)
+	require.NoError(t, err)
+	require.Len(t, cases, 3)
+
+	assert.Equal(t, "build", cases[0].Name)
+	assert.Equal(t, filepath.Join(dir, "build.yml"), cases[0].File)
+	assert.Equal(t, []string{"node:20", "os:linux", "os:windows"}, cases[0].MatrixArgs())
+	require.Len(t, cases[0].Mocks, 1)
+	assert.Equal(t, "outputs", cases[0].Mocks[0].String())
+	assert.Equal(t, "https://example.com", cases[0].Expect.Jobs["build"].Steps["deploy"].Outputs["url"])
+
+	assert.Equal(t, "dispatch", cases[1].Name)
+	assert.Equal(t, map[string]string{"version": "1.0"}, cases[1].Inputs)
+
+	assert.Equal(t, "deploy", cases[2].Name)
+	assert.Equal(t, "failure", cases[2].Expect.Result)
+}
+
+func TestReadCasesInvalid(t *testing.T) {
+	table := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"missing event", "name: build\n", "invalid test case 'build'"},
+		{"invalid mock", "event: push\nmocks:\n  - id: deploy\n", "invalid mock 1"},
+		{"invalid yaml", "event: [push\n", "failed to read the test case"},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "case.yml")
+			require.NoError(t, os.WriteFile(file, []byte(tt.content), 0o600))
+			_, err := ReadCases(file)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
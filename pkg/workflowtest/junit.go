@@ -0,0 +1,50 @@
+package workflowtest
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nektos/act/pkg/report"
+)
+
+// WriteJUnit writes the results of test cases as JUnit XML, with a testsuite per file and a testcase per test case
+func WriteJUnit(w io.Writer, results []*Result) error {
+	suites := report.JUnitTestSuites{Name: "act test"}
+	var total time.Duration
+	durations := []time.Duration{}
+	index := map[string]int{}
+	for _, result := range results {
+		i, ok := index[result.Case.File]
+		if !ok {
+			i = len(suites.Suites)
+			index[result.Case.File] = i
+			suites.Suites = append(suites.Suites, report.JUnitTestSuite{Name: result.Case.File})
+			durations = append(durations, 0)
+		}
+		suite := &suites.Suites[i]
+		testCase := report.JUnitTestCase{
+			Name:      result.Case.Name,
+			ClassName: result.Case.File,
+			Time:      report.JUnitTime(result.Duration),
+		}
+		if !result.Passed() {
+			testCase.Failure = &report.JUnitFailure{
+				Message: result.Failures[0],
+				Text:    strings.Join(result.Failures, "\n"),
+			}
+			suite.Failures++
+			suites.Failures++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+		durations[i] += result.Duration
+		suites.Tests++
+		total += result.Duration
+	}
+	for i := range suites.Suites {
+		suites.Suites[i].Time = report.JUnitTime(durations[i])
+	}
+	suites.Time = report.JUnitTime(total)
+	return report.WriteJUnitTestSuites(w, suites)
+}
@@ -0,0 +1,24 @@
+package workflowtest
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// WriteResults prints whether the test cases passed, the failures of the failed ones and the totals
+func WriteResults(w io.Writer, results []*Result) {
+	passed := 0
+	for _, result := range results {
+		if result.Passed() {
+			passed++
+			fmt.Fprintf(w, "PASS  %s (%v)\n", result.Case.Name, result.Duration.Round(time.Millisecond))
+			continue
+		}
+		fmt.Fprintf(w, "FAIL  %s (%v)\n", result.Case.Name, result.Duration.Round(time.Millisecond))
+		for _, failure := range result.Failures {
+			fmt.Fprintf(w, "      %s\n", failure)
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d failed\n", passed, len(results)-passed)
+}
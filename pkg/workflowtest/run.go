@@ -0,0 +1,205 @@
+package workflowtest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nektos/act/pkg/artifacts"
+	"github.com/nektos/act/pkg/runner"
+)
+
+// Run collects the records of the jobs and the uploaded artifacts of the run of a test case
+type Run struct {
+	Case *Case
+
+	mu        sync.Mutex
+	records   []runner.JobRecord
+	artifacts []string
+	result    string
+	startedAt time.Time
+}
+
+// Result is the result of a test case, it passed if it has no failures
+type Result struct {
+	Case       *Case
+	Records    []runner.JobRecord
+	Artifacts  []string
+	Err        error  // error of the run
+	Conclusion string // result of the run: success, failure or cancelled
+	Failures   []string
+	Duration   time.Duration
+}
+
+// NewRun starts the run of a test case
+func NewRun(testCase *Case) *Run {
+	return &Run{Case: testCase, startedAt: time.Now()}
+}
+
+// Configure adds the mocks of the test case to the config of the run and collects the records of its jobs, the result
+// of the run and the artifacts uploaded to the artifact server served with the returned context
+func (r *Run) Configure(ctx context.Context, config *runner.Config) context.Context {
+	// the mocks of the case take precedence over the overrides of --step-overrides
+	config.StepOverrides = append(slices.Clone(r.Case.Mocks), config.StepOverrides...)
+	recordJob := config.RecordJob
+	config.RecordJob = func(record runner.JobRecord) {
+		if recordJob != nil {
+			recordJob(record)
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.records = append(r.records, record)
+	}
+	events := config.Events
+	config.Events = func(event runner.Event) {
+		if events != nil {
+			events(event)
+		}
+		if event.Type != runner.EventRunFinished {
+			return
+		}
+		// workflows chained by workflow_run finish their own runs
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.result = runner.MergeJobResults(r.result, event.Result)
+	}
+	return artifacts.WithUploadHandler(ctx, func(_ string, name string, _ int64) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.artifacts = append(r.artifacts, name)
+	})
+}
+
+// Finish checks the results of the run against the expectations of the test case
+func (r *Run) Finish(err error) *Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := &Result{
+		Case:      r.Case,
+		Records:   r.records,
+		Artifacts: r.artifacts,
+		Err:       err,
+		Duration:  time.Since(r.startedAt),
+	}
+	// the run may fail before or after the workflows ran, e.g. if they can't be planned
+	switch {
+	case r.result == "" && err == nil:
+		result.Conclusion = "success"
+	case r.result == "" || (r.result == "success" && err != nil):
+		result.Conclusion = "failure"
+	default:
+		result.Conclusion = r.result
+	}
+	result.Failures = r.Case.Expect.check(result)
+	return result
+}
+
+// Passed reports whether the results of the run matched the expectations of the test case
+func (r *Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+func (e *Expect) check(result *Result) []string {
+	failures := []string{}
+	expected := e.Result
+	if expected == "" {
+		expected = "success"
+	}
+	if result.Conclusion != expected {
+		failure := fmt.Sprintf("expected the run to end with %s, got %s", expected, result.Conclusion)
+		if result.Err != nil {
+			failure = fmt.Sprintf("%s: %v", failure, result.Err)
+		}
+		failures = append(failures, failure)
+	}
+
+	for _, jobID := range sortedKeys(e.Jobs) {
+		records := []runner.JobRecord{}
+		for _, record := range result.Records {
+			if record.JobID == jobID || record.Name == jobID {
+				records = append(records, record)
+			}
+		}
+		if len(records) == 0 {
+			failures = append(failures, fmt.Sprintf("job %s did not run", jobID))
+			continue
+		}
+		for _, record := range records {
+			failures = append(failures, e.Jobs[jobID].check(record)...)
+		}
+	}
+
+	for _, name := range e.Artifacts {
+		if !slices.Contains(result.Artifacts, name) {
+			failures = append(failures, fmt.Sprintf("artifact %s was not uploaded", name))
+		}
+	}
+
+	logs := []string{}
+	for _, record := range result.Records {
+		logs = append(logs, record.Log)
+	}
+	failures = append(failures, checkLogs("the run", strings.Join(logs, "\n"), e.Logs)...)
+	return failures
+}
+
+func (e *JobExpect) check(record runner.JobRecord) []string {
+	failures := []string{}
+	job := fmt.Sprintf("job %s", record.Name)
+	if e.Result != "" && e.Result != record.Result {
+		failures = append(failures, fmt.Sprintf("%s: expected result %s, got %s", job, e.Result, record.Result))
+	}
+	failures = append(failures, checkOutputs(job, record.Outputs, e.Outputs)...)
+	failures = append(failures, checkLogs(job, record.Log, e.Logs)...)
+
+	for _, stepID := range sortedKeys(e.Steps) {
+		index := slices.IndexFunc(record.Steps, func(step runner.StepRecord) bool {
+			return step.ID == stepID || step.Name == stepID
+		})
+		if index < 0 {
+			failures = append(failures, fmt.Sprintf("%s: step %s did not run", job, stepID))
+			continue
+		}
+		failures = append(failures, e.Steps[stepID].check(fmt.Sprintf("%s, step %s", job, stepID), record.Steps[index])...)
+	}
+	return failures
+}
+
+func (e *StepExpect) check(step string, record runner.StepRecord) []string {
+	failures := []string{}
+	if e.Outcome != "" && e.Outcome != record.Outcome {
+		failures = append(failures, fmt.Sprintf("%s: expected outcome %s, got %s", step, e.Outcome, record.Outcome))
+	}
+	if e.Conclusion != "" && e.Conclusion != record.Conclusion {
+		failures = append(failures, fmt.Sprintf("%s: expected conclusion %s, got %s", step, e.Conclusion, record.Conclusion))
+	}
+	failures = append(failures, checkOutputs(step, record.Outputs, e.Outputs)...)
+	failures = append(failures, checkLogs(step, record.Log, e.Logs)...)
+	return failures
+}
+
+func checkOutputs(name string, outputs map[string]string, expected map[string]string) []string {
+	failures := []string{}
+	for _, output := range sortedKeys(expected) {
+		value, ok := outputs[output]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s: output %s is not set", name, output))
+		} else if value != expected[output] {
+			failures = append(failures, fmt.Sprintf("%s: expected output %s to be %q, got %q", name, output, expected[output], value))
+		}
+	}
+	return failures
+}
+
+func checkLogs(name string, log string, substrings []string) []string {
+	failures := []string{}
+	for _, substring := range substrings {
+		if !strings.Contains(log, substring) {
+			failures = append(failures, fmt.Sprintf("%s: the log does not contain %q", name, substring))
+		}
+	}
+	return failures
+}
@@ -0,0 +1,165 @@
+package workflowtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nektos/act/pkg/runner"
+)
+
+func newTestRecords() []runner.JobRecord {
+	return []runner.JobRecord{
+		{
+			JobID:   "build",
+			Name:    "build (linux)",
+			Result:  "success",
+			Outputs: map[string]string{"version": "1.0"},
+			Steps: []runner.StepRecord{
+				{ID: "test", Name: "test", Outcome: "failure", Conclusion: "success", Log: "3 tests failed"},
+				{ID: "deploy", Name: "deploy", Outcome: "success", Conclusion: "success", Outputs: map[string]string{"url": "https://example.com"}},
+			},
+			Log: "Run build\n3 tests failed\n",
+		},
+	}
+}
+
+func TestRunFinish(t *testing.T) {
+	table := []struct {
+		name     string
+		expect   Expect
+		result   string // result of the run finished event
+		err      error
+		failures []string
+	}{
+		{
+			name: "passed",
+			expect: Expect{
+				Jobs: map[string]*JobExpect{
+					"build (linux)": {
+						Result:  "success",
+						Outputs: map[string]string{"version": "1.0"},
+						Steps: map[string]*StepExpect{
+							"test":   {Outcome: "failure", Conclusion: "success", Logs: []string{"tests failed"}},
+							"deploy": {Outputs: map[string]string{"url": "https://example.com"}},
+						},
+					},
+				},
+				Artifacts: []string{"dist"},
+				Logs:      []string{"Run build"},
+			},
+			failures: []string{},
+		},
+		{
+			name:     "expected failure",
+			expect:   Expect{Result: "failure"},
+			result:   "failure",
+			err:      errors.New("Job 'build' failed"),
+			failures: []string{},
+		},
+		{
+			name:     "expected cancellation",
+			expect:   Expect{Result: "cancelled"},
+			result:   "cancelled",
+			err:      context.Canceled,
+			failures: []string{},
+		},
+		{
+			name:     "failed before the run",
+			expect:   Expect{Result: "cancelled"},
+			err:      errors.New("no stages to run"),
+			failures: []string{"expected the run to end with cancelled, got failure: no stages to run"},
+		},
+		{
+			name: "failed",
+			expect: Expect{
+				Jobs: map[string]*JobExpect{
+					"build": {
+						Result:  "failure",
+						Outputs: map[string]string{"version": "2.0", "sha": "abc"},
+						Steps: map[string]*StepExpect{
+							"test":    {Outcome: "success", Logs: []string{"all tests passed"}},
+							"publish": {Outcome: "success"},
+						},
+						Logs: []string{"Run deploy"},
+					},
+					"lint": {Result: "success"},
+				},
+				Artifacts: []string{"dist", "coverage"},
+			},
+			result: "failure",
+			err:    errors.New("Job 'build' failed"),
+			failures: []string{
+				"expected the run to end with success, got failure: Job 'build' failed",
+				"job build (linux): expected result failure, got success",
+				"job build (linux): output sha is not set",
+				`job build (linux): expected output version to be "2.0", got "1.0"`,
+				`job build (linux): the log does not contain "Run deploy"`,
+				"job build (linux): step publish did not run",
+				"job build (linux), step test: expected outcome success, got failure",
+				`job build (linux), step test: the log does not contain "all tests passed"`,
+				"job lint did not run",
+				"artifact coverage was not uploaded",
+			},
+		},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			run := NewRun(&Case{Name: tt.name, Event: "push", Expect: tt.expect})
+			config := &runner.Config{}
+			_ = run.Configure(context.Background(), config)
+			for _, record := range newTestRecords() {
+				config.RecordJob(record)
+			}
+			run.artifacts = append(run.artifacts, "dist")
+			if tt.result != "" {
+				config.Events(runner.Event{Type: runner.EventRunFinished, Result: tt.result})
+			}
+
+			result := run.Finish(tt.err)
+			assert.Equal(t, tt.failures, result.Failures)
+			assert.Equal(t, len(tt.failures) == 0, result.Passed())
+		})
+	}
+}
+
+func TestRunConfigure(t *testing.T) {
+	mock := &runner.StepOverride{ID: "deploy", Skip: true}
+	override := &runner.StepOverride{ID: "notify", Skip: true}
+	recorded := []string{}
+	config := &runner.Config{
+		StepOverrides: []*runner.StepOverride{override},
+		RecordJob: func(record runner.JobRecord) {
+			recorded = append(recorded, record.JobID)
+		},
+	}
+	run := NewRun(&Case{Event: "push", Mocks: []*runner.StepOverride{mock}})
+	_ = run.Configure(context.Background(), config)
+
+	assert.Equal(t, []*runner.StepOverride{mock, override}, config.StepOverrides)
+	config.RecordJob(runner.JobRecord{JobID: "build"})
+	assert.Equal(t, []string{"build"}, recorded)
+	assert.Len(t, run.Finish(nil).Records, 1)
+}
+
+func TestWriteResults(t *testing.T) {
+	results := []*Result{
+		{Case: &Case{Name: "build", File: "tests/build.yml"}, Failures: []string{}},
+		{Case: &Case{Name: "deploy", File: "tests/deploy.yml"}, Failures: []string{"job deploy did not run", "artifact dist was not uploaded"}},
+	}
+
+	out := &bytes.Buffer{}
+	WriteResults(out, results)
+	assert.Equal(t, "PASS  build (0s)\nFAIL  deploy (0s)\n      job deploy did not run\n      artifact dist was not uploaded\n\n1 passed, 1 failed\n", out.String())
+
+	junit := &bytes.Buffer{}
+	require.NoError(t, WriteJUnit(junit, results))
+	assert.Contains(t, junit.String(), `<testsuites name="act test" tests="2" failures="1" skipped="0" time="0.000">`)
+	assert.Contains(t, junit.String(), `<testsuite name="tests/build.yml" tests="1" failures="0" skipped="0" time="0.000">`)
+	assert.Contains(t, junit.String(), `<testcase name="build" classname="tests/build.yml" time="0.000"></testcase>`)
+	assert.Contains(t, junit.String(), `<failure message="job deploy did not run">job deploy did not run&#xA;artifact dist was not uploaded</failure>`)
+}